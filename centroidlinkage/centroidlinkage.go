@@ -0,0 +1,32 @@
+// Package centroidlinkage provides basic semantics for choosing different
+// clusters that are best fitted for centroid clustering (UPGMC)
+package centroidlinkage
+
+import "github.com/hoenirvili/cluster/lancewilliams"
+
+// CentroidLinkage type that represents the centroid linkage
+// bottom up cluster semantics
+type CentroidLinkage struct{}
+
+// NewCentroidLinkage creates a new CentroidLinkage pointer
+func NewCentroidLinkage() *CentroidLinkage {
+	return &CentroidLinkage{}
+}
+
+var _ lancewilliams.Linkage = (*CentroidLinkage)(nil)
+
+// Coefficients returns the centroid linkage coefficients
+// αi = ni/n, αj = nj/n, β = -ni*nj/n^2 and γ = 0
+// where n = ni+nj
+func (c CentroidLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	n := float64(ni + nj)
+	return lancewilliams.Coefficients{
+		AlphaI: float64(ni) / n,
+		AlphaJ: float64(nj) / n,
+		Beta:   -float64(ni*nj) / (n * n),
+	}
+}
+
+// Squared returns true, the centroid linkage formula is
+// applied on the squared euclidean distances
+func (c CentroidLinkage) Squared() bool { return true }
@@ -0,0 +1,29 @@
+package centroidlinkage_test
+
+import (
+	"github.com/hoenirvili/cluster/centroidlinkage"
+	"github.com/hoenirvili/cluster/lancewilliams"
+	gc "gopkg.in/check.v1"
+)
+
+type centroidLinkageSuite struct{}
+
+var _ = gc.Suite(&centroidLinkageSuite{})
+
+func (l centroidLinkageSuite) TestNewCentroidLinkage(c *gc.C) {
+	cl := centroidlinkage.NewCentroidLinkage()
+	c.Assert(cl, gc.NotNil)
+	c.Assert(cl.Squared(), gc.Equals, true)
+}
+
+func (l centroidLinkageSuite) TestCentroidLinkageCoefficients(c *gc.C) {
+	cl := centroidlinkage.NewCentroidLinkage()
+	coefficients := cl.Coefficients(1, 3, 2)
+	c.Assert(coefficients, gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.25, AlphaJ: 0.75, Beta: -0.1875, Gamma: 0,
+	})
+
+	// 1/2*16 + 1/2*9 - 1/4*1 = 49 / 4
+	coefficients = cl.Coefficients(1, 1, 1)
+	c.Assert(coefficients.Distance(16, 9, 1), gc.Equals, 49.0/4.0)
+}
@@ -2,11 +2,20 @@
 package cluster
 
 import (
+	"context"
+	"math"
+	"sort"
+
 	"github.com/hoenirvili/cluster/averagelinkage"
+	"github.com/hoenirvili/cluster/centroidlinkage"
 	"github.com/hoenirvili/cluster/completelinkage"
 	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/medianlinkage"
 	"github.com/hoenirvili/cluster/set"
 	"github.com/hoenirvili/cluster/singlelinkage"
+	"github.com/hoenirvili/cluster/wardlinkage"
+	"github.com/hoenirvili/cluster/weightedlinkage"
 )
 
 // strategy represents the type used
@@ -30,20 +39,70 @@ const (
 	//  “r” and “s” to the left is equal to the average
 	// length each arrow between connecting the points of one cluster to the other.
 	AverageLinkage
+	// WardLinkage hierarchical clustering, at each step combines the two clusters
+	// whose merge leads to the minimum increase of the total within-cluster variance.
+	// The distance between two clusters is computed from the squared euclidean
+	// distances of their points and the sizes of the clusters.
+	WardLinkage
+	// CentroidLinkage hierarchical clustering (UPGMC), the distance between two
+	// clusters is the euclidean distance between their centroids.
+	// The merge distances are not guaranteed to increase, a merge can
+	// happen at a lower distance than the previous one (inversion).
+	CentroidLinkage
+	// MedianLinkage hierarchical clustering (WPGMC), the distance between two
+	// clusters is the euclidean distance between their weighted centroids, where
+	// the centroid of a merged cluster is the midpoint of the two centroids merged.
+	// The merge distances are not guaranteed to increase, a merge can
+	// happen at a lower distance than the previous one (inversion).
+	MedianLinkage
+	// WeightedLinkage hierarchical clustering (WPGMA), also known as McQuitty's method,
+	// the distance between a merged cluster and another cluster is defined as the mean
+	// of the distances from the two merged clusters to the other cluster,
+	// regardless of how many points each of them holds.
+	WeightedLinkage
 )
 
-// swapper defines the criteria of which we swap and
-// replace the best point based on the cluster strategy
-// implementation and recompute their distances
-type swapper interface {
-	// Swap swaps the first distance with the second distance
-	// based on the cluster algorithm
-	Swap(first, second distance.Distance)
+// Linkage defines the criteria of which the distances are
+// recomputed after two clusters are merged, in terms of the
+// Lance-Williams coefficients. Every strategy has its own Linkage
+// implementation, custom clustering criteria can be used by
+// implementing this interface and passing it to FitWith
+type Linkage interface {
+	lancewilliams.Linkage
+}
+
+var (
+	_ Linkage = (*singlelinkage.SingleLinkage)(nil)
+	_ Linkage = (*completelinkage.CompleteLinkage)(nil)
+	_ Linkage = (*averagelinkage.AverageLinkage)(nil)
+	_ Linkage = (*wardlinkage.WardLinkage)(nil)
+	_ Linkage = (*centroidlinkage.CentroidLinkage)(nil)
+	_ Linkage = (*medianlinkage.MedianLinkage)(nil)
+	_ Linkage = (*weightedlinkage.WeightedLinkage)(nil)
+	_ Linkage = (*lancewilliams.Flexible)(nil)
+)
 
-	// Recompute recomputes the remaining distances after
-	// the swap process is done based on the cluster provided and returns the best
-	// distance alongside with the keys of the map of distances that should be removed
-	Recompute(based set.Set, on distance.Distance) (best float64, deleted []set.Set)
+// newLinkage returns the Linkage implementation of the strategy
+// If the strategy is not known this will return nil
+func newLinkage(s strategy) Linkage {
+	switch s {
+	case SingleLinkage:
+		return singlelinkage.NewSingleLinkage()
+	case CompleteLinkage:
+		return completelinkage.NewCompleteLinkage()
+	case AverageLinkage:
+		return averagelinkage.NewAverageLinkage()
+	case WardLinkage:
+		return wardlinkage.NewWardLinkage()
+	case CentroidLinkage:
+		return centroidlinkage.NewCentroidLinkage()
+	case MedianLinkage:
+		return medianlinkage.NewMedianLinkage()
+	case WeightedLinkage:
+		return weightedlinkage.NewWeightedLinkage()
+	}
+
+	return nil
 }
 
 // Fit will fit the points in k clusters based on the strategy of clustering
@@ -53,131 +112,366 @@ func Fit(points []distance.Distance, s strategy, k int) []set.Set {
 		return nil
 	}
 
-	// don't modify the original slice, make a copy out of it
-	table := make([]distance.Distance, len(points), len(points))
-	for key, row := range points {
-		table[key].Set = row.Set
-		table[key].Points = make(map[set.Set]float64, len(row.Points))
-		for mkey, col := range row.Points {
-			table[key].Points[mkey] = col
-		}
+	return FitWith(points, newLinkage(s), k)
+}
+
+// FitTies will fit the points in k clusters the same way Fit does,
+// choosing the pair of clusters merged with the tie break policy when
+// several pairs are at the same distance.
+// If k is not in the range of the points this will return nil
+func FitTies(points []distance.Distance, s strategy, k int, ties TieBreak) []set.Set {
+	if k <= 0 || k > len(points) {
+		return nil
 	}
 
-	var swapper swapper
-	switch s {
-	case SingleLinkage:
-		swapper = singlelinkage.NewSingleLinkage()
-	case CompleteLinkage:
-		swapper = completelinkage.NewCompleteLinkage()
-	case AverageLinkage:
-		swapper = averagelinkage.NewAverageLinkage(table)
+	d := FitDendrogramTies(points, s, ties)
+	if d == nil {
+		return nil
 	}
 
-	pair := struct{ first, second set.Set }{}
-	for n := len(table); k != n; n = len(table) {
-		bestDistance := -1.0
-		j := 0
-		for i := 0; i < n; i++ {
-			f, s, distance := table[i].Best()
-			if f == s {
-				continue
+	return d.Cut(k)
+}
+
+// FitChecked will fit the points in k clusters the same way Fit does,
+// but the strategy, the table of distances and k are validated first,
+// in this order. Instead of returning nil or panicking on bad input this
+// will return one of UnknownStrategyError, the errors returned by Validate
+// or InvalidKError
+func FitChecked(points []distance.Distance, s strategy, k int) ([]set.Set, error) {
+	return FitContext(context.Background(), points, s, k, nil)
+}
+
+// FitContext will fit the points in k clusters the same way FitChecked does.
+// The context is checked between merges, if it is cancelled this will stop
+// and return the error of the context. If progress is not nil it is called
+// after every merge, as soon as it is made, with the number of clusters left
+// and the merge distance. The faster algorithms used for the single linkage
+// and the reducible linkages find the merges out of order so they are not
+// used when progress is not nil
+func FitContext(ctx context.Context, points []distance.Distance, s strategy, k int, progress Progress) ([]set.Set, error) {
+	linkage := newLinkage(s)
+	if linkage == nil {
+		return nil, UnknownStrategyError{Strategy: uint8(s)}
+	}
+	if err := Validate(points); err != nil {
+		return nil, err
+	}
+	if k <= 0 || k > len(points) {
+		return nil, InvalidKError{K: k, N: len(points)}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cls := leaves(points)
+	steps, err := hierarchy(distance.Condense(points), sizes(cls), linkage, nil, tracker{
+		ctx:      ctx,
+		progress: progress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return cut(cls, steps, func(n int, _ float64) bool {
+		return n == k
+	}), nil
+}
+
+// Validate checks that the table of distances can be fitted.
+// This returns EmptyTableError if the table has no rows, InvalidSetError
+// if the cluster of a row is not valid, DuplicateSetError if a point
+// is in the cluster of more than one row,
+// RaggedTableError if a distance is missing or it's to an unknown cluster
+// and InvalidDistanceError if a distance is NaN or negative
+func Validate(points []distance.Distance) error {
+	if len(points) == 0 {
+		return EmptyTableError{}
+	}
+
+	index := make(map[set.Set]int, len(points))
+	seen := make(map[string]bool, len(points))
+	for i, row := range points {
+		if !row.Set.Valid() {
+			return InvalidSetError{Set: row.Set}
+		}
+		for _, point := range row.Set.Slice() {
+			if seen[point] {
+				return DuplicateSetError{Set: row.Set}
 			}
+			seen[point] = true
+		}
+		index[row.Set] = i
+	}
 
-			if bestDistance == -1 || bestDistance > distance {
-				bestDistance = distance
-				pair.first, pair.second = f, s
-				j = i
-				continue
+	for _, row := range points {
+		unknown := make([]string, 0)
+		for cluster := range row.Points {
+			if _, ok := index[cluster]; !ok {
+				unknown = append(unknown, string(cluster))
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return RaggedTableError{First: row.Set, Second: set.Set(unknown[0]), Unknown: true}
+		}
+	}
+
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			first, second := points[i].Set, points[j].Set
+			d, ok := points[i].Points[second]
+			if !ok {
+				d, ok = points[j].Points[first]
+			}
+			if !ok {
+				return RaggedTableError{First: first, Second: second}
+			}
+			if math.IsNaN(d) || d < 0 {
+				return InvalidDistanceError{First: first, Second: second, Distance: d}
 			}
 		}
+	}
 
-		table[j].Merge(pair.second)
-		table = refit(table, pair.first, pair.second, swapper)
+	return nil
+}
+
+// FitWith will fit the points in k clusters based on the linkage
+// provided. This will return the k clusters that best fits the distance points
+// If the linkage is nil or k is not in the range of the points this will return nil
+func FitWith(points []distance.Distance, linkage Linkage, k int) []set.Set {
+	if k <= 0 || k > len(points) || linkage == nil {
+		return nil
 	}
 
-	cls := make([]set.Set, 0, k)
-	for _, c := range table {
-		cls = append(cls, c.Set)
+	cls := leaves(points)
+	steps, _ := hierarchy(distance.Condense(points), sizes(cls), linkage, nil, tracker{})
+	return cut(cls, steps, func(n int, _ float64) bool {
+		return n == k
+	})
+}
+
+// FitMatrix will fit the matrix of distances in k clusters based on the
+// strategy of clustering provided, the points are named x1, x2, x3, ...
+// same as distance.NewDistances names them.
+// This will return the k clusters that best fits the distance points
+// If k is not in the range of the points this will return nil
+func FitMatrix(m *distance.Matrix, s strategy, k int) []set.Set {
+	linkage := newLinkage(s)
+	if k <= 0 || k > m.Len() || linkage == nil {
+		return nil
+	}
+
+	cls := names(m.Len())
+	steps, _ := hierarchy(m, sizes(cls), linkage, nil, tracker{})
+	return cut(cls, steps, func(n int, _ float64) bool {
+		return n == k
+	})
+}
+
+// FitThreshold will fit the points based on the strategy of clustering
+// provided, merging clusters until the best linkage distance left
+// is greater than maxDistance. This will return the clusters that exist
+// at that point
+// If the table is empty or maxDistance is negative this will return nil
+func FitThreshold(points []distance.Distance, s strategy, maxDistance float64) []set.Set {
+	if len(points) == 0 || maxDistance < 0 || math.IsNaN(maxDistance) {
+		return nil
+	}
+
+	linkage := newLinkage(s)
+	if linkage == nil {
+		return nil
+	}
+
+	cls := leaves(points)
+	steps, _ := hierarchy(distance.Condense(points), sizes(cls), linkage, nil, tracker{})
+	return cut(cls, steps, func(_ int, best float64) bool {
+		return best > maxDistance
+	})
+}
+
+// leaves returns the clusters of the table of distances
+// before any merge is made
+func leaves(points []distance.Distance) []set.Set {
+	cls := make([]set.Set, 0, len(points))
+	for _, row := range points {
+		cls = append(cls, row.Set)
 	}
 
 	return cls
 }
 
-// refit refits all distance points based on the first and second clusters that has been
-// chosen in the i-th iteration. If the clusters provided are the same this will return the same
-// points
-func refit(points []distance.Distance, first, second set.Set, s swapper) []distance.Distance {
-	if first == second {
-		return points
+// names returns the clusters of n points named
+// same as distance.NewDistances names them
+func names(n int) []set.Set {
+	cls := make([]set.Set, n, n)
+	for i := range cls {
+		cls[i] = distance.Name(i)
 	}
 
-	n, j := len(points), 0
-	var base set.Set
-	for i := 0; i < n; i++ {
-		// we found the first cluster
-		if points[i].Set == first {
-			base = points[i].Set
-			for j = 0; j < n; j++ {
-				if i == j {
-					continue
-				}
-				// we found the second cluster
-				if points[j].Set == second {
-					s.Swap(points[i], points[j])
-					break
-				}
-			}
+	return cls
+}
+
+// replay applies the steps on n leaves until one cluster remains or
+// until stop returns true. Before every step stop receives the number
+// of clusters and the distance of the step.
+// This will return the points of the remaining clusters
+func replay(n int, steps []step, stop func(n int, best float64) bool) []set.IDs {
+	cls := make([]set.IDs, n, n)
+	for i := range cls {
+		cls[i] = set.IDs{i}
+	}
+
+	left := n
+	for _, s := range steps {
+		if stop(left, s.distance) {
 			break
 		}
+		cls[s.first].Add(cls[s.second])
+		cls[s.second] = nil
+		left--
+	}
+
+	remaining := make([]set.IDs, 0, left)
+	for _, c := range cls {
+		if c != nil {
+			remaining = append(remaining, c)
+		}
+	}
+
+	return remaining
+}
+
+// cut replays the steps on the leaves in the same way replay does
+// and returns the remaining clusters named after their leaves
+func cut(leaves []set.Set, steps []step, stop func(n int, best float64) bool) []set.Set {
+	ids := replay(len(leaves), steps, stop)
+	cls := make([]set.Set, 0, len(ids))
+	for _, c := range ids {
+		cls = append(cls, c.Set(leaves))
 	}
 
-	// we didn't find the second cluster
-	if j != n {
-		points = append(points[:j], points[j+1:]...)
+	return cls
+}
+
+// hierarchy computes every merge of the matrix of distances
+// until one cluster remains, size holds the number of points of every
+// cluster of the matrix. The single linkage merges are computed from the
+// minimum spanning tree and if the linkage is reducible the nearest neighbor
+// chain algorithm is tried first, falling back to the greedy algorithm if
+// they cannot guarantee the same merges.
+// Those algorithms break the ties the same way LowestIndex does so they
+// are only tried with that policy, if ties is nil LowestIndex is used.
+// They also find the merges in another order than the one they are made,
+// so if the tracker reports the progress only the greedy algorithm is used,
+// reporting every merge as soon as it is made.
+// If the fit is cancelled this will return the error of the context
+func hierarchy(m *distance.Matrix, size []int, linkage Linkage, ties TieBreak, t tracker) ([]step, error) {
+	if ties == nil {
+		ties = LowestIndex()
+	}
+	if _, ok := ties.(lowestIndex); !ok || t.progress != nil {
+		return greedy(m, size, linkage, ties, t)
+	}
+
+	if _, ok := linkage.(*singlelinkage.SingleLinkage); ok {
+		steps, ok, err := mst(m, size, t)
+		if err != nil || ok {
+			return steps, err
+		}
 	}
 
-	// check if the last is nil and if not
-	// remove all keys and assign it to nil
-	last := len(points) - 1
-	if points[last].Points != nil {
-		for key := range points[last].Points {
-			delete(points[last].Points, key)
+	if r, ok := linkage.(lancewilliams.Reducible); ok && r.Reducible() {
+		steps, ok, err := nnchain(m, size, linkage, t)
+		if err != nil || ok {
+			return steps, err
 		}
-		points[last].Points = nil
 	}
 
-	recomputeDistances(points, base, s)
-	return points
+	return greedy(m, size, linkage, ties, t)
 }
 
-// recomputeDistances recomputes the table of distances using
-// the base cluster as relative distances.
-func recomputeDistances(points []distance.Distance, base set.Set, s swapper) {
-	n := len(points)
-	for i := 0; i < n; i++ {
-		if points[i].Set == base {
-			continue
+// sizes returns the number of points of every cluster
+func sizes(cls []set.Set) []int {
+	n := make([]int, len(cls), len(cls))
+	for i, c := range cls {
+		n[i] = c.Len()
+	}
+
+	return n
+}
+
+// greedy merges at every step the closest pair of clusters of the whole
+// matrix of distances until one cluster remains. When several pairs of
+// clusters are at the same distance the pair merged is chosen by the
+// tie break policy. This will return every merge made
+func greedy(m *distance.Matrix, size []int, linkage Linkage, ties TieBreak, t tracker) ([]step, error) {
+	// don't modify the original matrix, make a copy out of it
+	if linkage.Squared() {
+		m = lancewilliams.Square(m)
+	} else {
+		m = m.Copy()
+	}
+	size = append([]int(nil), size...)
+
+	if ties == nil {
+		ties = LowestIndex()
+	}
+	choose := ties.breaker()
+
+	n := m.Len()
+	born := make([]int, n, n)
+	candidates := make([]pair, 0, n)
+	steps := make([]step, 0, n)
+	for left := n; left > 1; left-- {
+		bestDistance := -1.0
+		candidates = candidates[:0]
+		for r := 0; r < n; r++ {
+			if size[r] == 0 {
+				continue
+			}
+			for c := r + 1; c < n; c++ {
+				if size[c] == 0 {
+					continue
+				}
+				d := lancewilliams.Height(m.At(r, c), linkage)
+				switch {
+				case bestDistance == -1 || bestDistance > d:
+					bestDistance = d
+					candidates = append(candidates[:0], pair{i: r, j: c})
+				case bestDistance == d:
+					candidates = append(candidates, pair{i: r, j: c})
+				}
+			}
+		}
+
+		if bestDistance == -1 {
+			break
 		}
 
-		best, toDelete := s.Recompute(base, points[i])
-		if best == -1.0 || len(toDelete) == 0 {
-			continue
+		i, j := candidates[0].i, candidates[0].j
+		if len(candidates) > 1 {
+			p := candidates[choose(candidates, born)]
+			i, j = p.i, p.j
 		}
+		born[i] = len(steps) + 1
 
-		// delete all the unwanted keys that does not
-		// belong to the pair base
-		for _, key := range toDelete {
-			delete(points[i].Points, key)
+		s := step{
+			first:    i,
+			second:   j,
+			distance: bestDistance,
+			size:     size[i] + size[j],
 		}
+		if last := len(steps) - 1; last >= 0 {
+			s.inversion = bestDistance < steps[last].distance
+		}
+		steps = append(steps, s)
 
-		// find that point remaining point and delete it
-		// and create a new entry with base key and best value
-		for cluster := range points[i].Points {
-			if base.In(cluster) {
-				delete(points[i].Points, cluster)
-				points[i].Points[base] = best
-				break
-			}
+		lancewilliams.Update(m, size, i, j, linkage)
+		if err := t.merged(left-1, bestDistance); err != nil {
+			return nil, err
 		}
 	}
+
+	return steps, nil
 }
@@ -1,11 +1,16 @@
 package cluster_test
 
 import (
+	"math"
+
 	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/averagelinkage"
 	"github.com/hoenirvili/cluster/dimension/one"
 	"github.com/hoenirvili/cluster/dimension/two"
 	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/lancewilliams"
 	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/singlelinkage"
 	gc "gopkg.in/check.v1"
 )
 
@@ -176,8 +181,290 @@ func (cl clusterSuite) TestDistanceFitTwoAverageLinkage(c *gc.C) {
 	}
 }
 
+func (cl clusterSuite) TestDistanceFitOneWardLinkage(c *gc.C) {
+	expected := [][]set.Set{
+		{"x1,x2,x3,x4,x5,x6,x7,x8"},
+		{"x1,x2,x3,x4", "x5,x6,x7,x8"},
+		{"x1", "x2,x3,x4", "x5,x6,x7,x8"},
+		{"x1", "x2,x3,x4", "x5,x6", "x7,x8"},
+		{"x1", "x2,x3", "x4", "x5,x6", "x7,x8"},
+		{"x1", "x2,x3", "x4", "x5,x6", "x7", "x8"},
+		{"x1", "x2,x3", "x4", "x5", "x6", "x7", "x8"},
+		{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8"},
+	}
+
+	distances := cl.oneDistances(c)
+	iter := len(distances)
+	for i := iter; i > 0; i-- {
+		clusters := cluster.Fit(distances, cluster.WardLinkage, i)
+		c.Assert(clusters, gc.NotNil)
+		n := len(clusters)
+		c.Assert(n, gc.Equals, i)
+		c.Assert(clusters, gc.DeepEquals, expected[i-1])
+	}
+}
+
+func (cl clusterSuite) TestDistanceFitTwoWardLinkage(c *gc.C) {
+	expected := [][]set.Set{
+		{"x1,x2,x3,x4,x5,x6,x7,x8,x9,x10"},
+		{"x1,x2,x3,x4", "x5,x6,x7,x8,x9,x10"},
+		{"x1,x2,x3,x4", "x5,x6", "x7,x8,x9,x10"},
+		{"x1,x2", "x3,x4", "x5,x6", "x7,x8,x9,x10"},
+		{"x1,x2", "x3,x4", "x5,x6", "x7,x8", "x9,x10"},
+		{"x1,x2", "x3,x4", "x5", "x6", "x7,x8", "x9,x10"},
+		{"x1,x2", "x3,x4", "x5", "x6", "x7,x8", "x9", "x10"},
+		{"x1,x2", "x3,x4", "x5", "x6", "x7", "x8", "x9", "x10"},
+		{"x1,x2", "x3", "x4", "x5", "x6", "x7", "x8", "x9", "x10"},
+		{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8", "x9", "x10"},
+	}
+
+	distances := cl.twoDistances(c)
+	iter := len(distances)
+	for i := iter; i > 0; i-- {
+		clusters := cluster.Fit(distances, cluster.WardLinkage, i)
+		c.Assert(clusters, gc.NotNil)
+		n := len(clusters)
+		c.Assert(n, gc.Equals, i)
+		c.Assert(clusters, gc.DeepEquals, expected[i-1])
+	}
+}
+
+func (cl clusterSuite) TestDistanceFitWeightedLinkage(c *gc.C) {
+	points := one.NewDistances(5.8, 6.8, 8.6, 9.0, 9.3, 9.9)
+	distances := distance.NewDistances(points)
+
+	// the weighted average gives x6 the same weight as the whole
+	// x3,x4,x5 cluster so x1 and x2 are merged first
+	expected := [][]set.Set{
+		{"x1,x2,x3,x4,x5,x6"},
+		{"x1,x2", "x3,x4,x5,x6"},
+		{"x1,x2", "x3,x4,x5", "x6"},
+		{"x1", "x2", "x3,x4,x5", "x6"},
+		{"x1", "x2", "x3", "x4,x5", "x6"},
+		{"x1", "x2", "x3", "x4", "x5", "x6"},
+	}
+	for i := len(distances); i > 0; i-- {
+		clusters := cluster.Fit(distances, cluster.WeightedLinkage, i)
+		c.Assert(clusters, gc.DeepEquals, expected[i-1])
+	}
+
+	// unlike the average that merges x6 into the x3,x4,x5 cluster first
+	clusters := cluster.Fit(distances, cluster.AverageLinkage, 3)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1", "x2", "x3,x4,x5,x6"})
+}
+
+// maxLinkage is a custom linkage that always keeps
+// the greatest distance, same as the complete linkage
+type maxLinkage struct{}
+
+func (m maxLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5, Gamma: 0.5}
+}
+
+func (m maxLinkage) Squared() bool { return false }
+
+func (cl clusterSuite) TestFitWith(c *gc.C) {
+	distances := cl.twoDistances(c)
+	clusters := cluster.FitWith(distances, nil, 2)
+	c.Assert(clusters, gc.IsNil)
+	clusters = cluster.FitWith(distances, singlelinkage.NewSingleLinkage(), 0)
+	c.Assert(clusters, gc.IsNil)
+
+	for k := len(distances); k > 0; k-- {
+		clusters = cluster.FitWith(distances, singlelinkage.NewSingleLinkage(), k)
+		c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, cluster.SingleLinkage, k))
+		clusters = cluster.FitWith(distances, averagelinkage.NewAverageLinkage(), k)
+		c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, cluster.AverageLinkage, k))
+
+		clusters = cluster.FitWith(distances, maxLinkage{}, k)
+		c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, cluster.CompleteLinkage, k))
+	}
+}
+
+func (cl clusterSuite) TestFitWithFlexible(c *gc.C) {
+	distances := cl.oneDistances(c)
+
+	// with beta 0 the flexible linkage is the weighted average linkage
+	flexible := lancewilliams.NewFlexible(0)
+	for k := len(distances); k > 0; k-- {
+		clusters := cluster.FitWith(distances, flexible, k)
+		c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, cluster.WeightedLinkage, k))
+	}
+
+	flexible = lancewilliams.NewFlexible(-0.25)
+	clusters := cluster.FitWith(distances, flexible, 3)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1", "x2,x3,x4", "x5,x6,x7,x8"})
+}
+
+func (cl clusterSuite) TestFitThresholdInvalid(c *gc.C) {
+	distances := cl.oneDistances(c)
+	clusters := cluster.FitThreshold(nil, cluster.SingleLinkage, 1)
+	c.Assert(clusters, gc.IsNil)
+	clusters = cluster.FitThreshold(distances, cluster.SingleLinkage, -1)
+	c.Assert(clusters, gc.IsNil)
+}
+
+func (cl clusterSuite) TestFitThresholdOne(c *gc.C) {
+	distances := cl.oneDistances(c)
+	tests := []struct {
+		maxDistance float64
+		single      []set.Set
+		complete    []set.Set
+		average     []set.Set
+	}{
+		{
+			maxDistance: 0.05,
+			single:      []set.Set{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8"},
+			complete:    []set.Set{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8"},
+			average:     []set.Set{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8"},
+		},
+		{
+			maxDistance: 0.1,
+			single:      []set.Set{"x1", "x2,x3", "x4", "x5,x6", "x7,x8"},
+			complete:    []set.Set{"x1", "x2,x3", "x4", "x5,x6", "x7,x8"},
+			average:     []set.Set{"x1", "x2,x3", "x4", "x5,x6", "x7,x8"},
+		},
+		{
+			maxDistance: 0.25,
+			single:      []set.Set{"x1", "x2,x3,x4", "x5,x6,x7,x8"},
+			complete:    []set.Set{"x1", "x2,x3", "x4", "x5,x6", "x7,x8"},
+			average:     []set.Set{"x1", "x2,x3,x4", "x5,x6", "x7,x8"},
+		},
+		{
+			maxDistance: 1.5,
+			single:      []set.Set{"x1,x2,x3,x4,x5,x6,x7,x8"},
+			complete:    []set.Set{"x1,x2,x3,x4", "x5,x6,x7,x8"},
+			average:     []set.Set{"x1,x2,x3,x4", "x5,x6,x7,x8"},
+		},
+	}
+
+	for _, test := range tests {
+		clusters := cluster.FitThreshold(distances, cluster.SingleLinkage, test.maxDistance)
+		c.Assert(clusters, gc.DeepEquals, test.single)
+		clusters = cluster.FitThreshold(distances, cluster.CompleteLinkage, test.maxDistance)
+		c.Assert(clusters, gc.DeepEquals, test.complete)
+		clusters = cluster.FitThreshold(distances, cluster.AverageLinkage, test.maxDistance)
+		c.Assert(clusters, gc.DeepEquals, test.average)
+	}
+}
+
+func (cl clusterSuite) TestFitThresholdTwo(c *gc.C) {
+	distances := cl.twoDistances(c)
+	clusters := cluster.FitThreshold(distances, cluster.SingleLinkage, 2)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1,x2,x3,x4", "x5,x6", "x7,x8,x9,x10"})
+	clusters = cluster.FitThreshold(distances, cluster.CompleteLinkage, 2)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1,x2", "x3,x4", "x5,x6", "x7,x8,x9,x10"})
+	clusters = cluster.FitThreshold(distances, cluster.AverageLinkage, 2)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1,x2,x3,x4", "x5,x6", "x7,x8,x9,x10"})
+}
+
+func (cl clusterSuite) TestFitMatrix(c *gc.C) {
+	points := two.NewDistances(
+		[]float64{1, 1.5, 5, 3, 4, 3, 6.5, 7, 2.2, 9.1},
+		[]float64{1, 1.5, 5, 4, 4, 3.5, 2.1, 8.3, 6.4, 0.3},
+	)
+	distances := distance.NewDistances(points)
+	matrix := distance.NewMatrix(points)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		for k := len(points); k > 0; k-- {
+			expected := cluster.Fit(distances, s, k)
+			clusters := cluster.FitMatrix(matrix, s, k)
+			c.Assert(clusters, gc.DeepEquals, expected)
+		}
+	}
+
+	// the matrix is never modified
+	c.Assert(matrix, gc.DeepEquals, distance.NewMatrix(points))
+	c.Assert(cluster.FitMatrix(matrix, cluster.SingleLinkage, 0), gc.IsNil)
+	c.Assert(cluster.FitMatrix(matrix, cluster.SingleLinkage, 11), gc.IsNil)
+}
+
+func (cl clusterSuite) TestFitChecked(c *gc.C) {
+	distances := cl.oneDistances(c)
+	clusters, err := cluster.FitChecked(distances, cluster.AverageLinkage, 3)
+	c.Assert(err, gc.IsNil)
+	c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, cluster.AverageLinkage, 3))
+
+	_, err = cluster.FitChecked(distances, cluster.WeightedLinkage+1, 3)
+	c.Assert(err, gc.Equals, cluster.UnknownStrategyError{Strategy: 7})
+
+	_, err = cluster.FitChecked(distances, cluster.AverageLinkage, 0)
+	c.Assert(err, gc.Equals, cluster.InvalidKError{K: 0, N: 8})
+
+	_, err = cluster.FitChecked(distances, cluster.AverageLinkage, 9)
+	c.Assert(err, gc.Equals, cluster.InvalidKError{K: 9, N: 8})
+
+	_, err = cluster.FitChecked(nil, cluster.AverageLinkage, 1)
+	c.Assert(err, gc.Equals, cluster.EmptyTableError{})
+}
+
+func (cl clusterSuite) TestValidate(c *gc.C) {
+	c.Assert(cluster.Validate(cl.oneDistances(c)), gc.IsNil)
+	c.Assert(cluster.Validate(cl.twoDistances(c)), gc.IsNil)
+	c.Assert(cluster.Validate([]distance.Distance{{Set: "x1"}}), gc.IsNil)
+
+	tests := []struct {
+		table []distance.Distance
+		err   error
+	}{
+		{nil, cluster.EmptyTableError{}},
+		{
+			[]distance.Distance{{Set: "x1", Points: map[set.Set]float64{"point": 1}}, {Set: "point"}},
+			cluster.InvalidSetError{Set: "point"},
+		},
+		{
+			[]distance.Distance{{Set: "x1", Points: map[set.Set]float64{"x2": 1}}, {Set: "x1"}},
+			cluster.DuplicateSetError{Set: "x1"},
+		},
+		{
+			[]distance.Distance{{Set: "x1,x2", Points: map[set.Set]float64{"x2": 1}}, {Set: "x2"}},
+			cluster.DuplicateSetError{Set: "x2"},
+		},
+		{
+			[]distance.Distance{{Set: "x2", Points: map[set.Set]float64{"x1,x2": 1}}, {Set: "x1,x2"}},
+			cluster.DuplicateSetError{Set: "x1,x2"},
+		},
+		{
+			[]distance.Distance{{Set: "x1", Points: map[set.Set]float64{"x3": 1, "x4": 1}}, {Set: "x2"}},
+			cluster.RaggedTableError{First: "x1", Second: "x3", Unknown: true},
+		},
+		{
+			[]distance.Distance{
+				{Set: "x1", Points: map[set.Set]float64{"x2": 1}},
+				{Set: "x2"},
+				{Set: "x3", Points: map[set.Set]float64{"x2": 1}},
+			},
+			cluster.RaggedTableError{First: "x1", Second: "x3"},
+		},
+		{
+			[]distance.Distance{{Set: "x1", Points: map[set.Set]float64{"x2": -1}}, {Set: "x2"}},
+			cluster.InvalidDistanceError{First: "x1", Second: "x2", Distance: -1},
+		},
+	}
+
+	for _, test := range tests {
+		c.Assert(cluster.Validate(test.table), gc.Equals, test.err)
+	}
+
+	// the distance can be stored in any of the two rows
+	err := cluster.Validate([]distance.Distance{
+		{Set: "x1"},
+		{Set: "x2", Points: map[set.Set]float64{"x1": math.NaN()}},
+	})
+	c.Assert(err, gc.FitsTypeOf, cluster.InvalidDistanceError{})
+	c.Assert(math.IsNaN(err.(cluster.InvalidDistanceError).Distance), gc.Equals, true)
+}
+
 // benchmarks
 
+func (cl clusterSuite) BenchmarkFitMatrixAverageLinkage(c *gc.C) {
+	points := nnchainSuite{}.randomPoints(1, 1000)
+	matrix := distance.NewMatrix(points)
+	for i := 0; i < c.N; i++ {
+		cluster.FitMatrix(matrix, cluster.AverageLinkage, 1)
+	}
+}
+
 func (cl clusterSuite) BenchmarkFitOneSingleLinkage(c *gc.C) {
 	distances := cl.oneDistances(c)
 	for i := 0; i < c.N; i++ {
@@ -2,14 +2,9 @@
 // clusters that are best fitted for complete linkage clustering
 package completelinkage
 
-import (
-	"math"
+import "github.com/hoenirvili/cluster/lancewilliams"
 
-	"github.com/hoenirvili/cluster/distance"
-	"github.com/hoenirvili/cluster/set"
-)
-
-// CompleteLinkage type that represents the single linkage
+// CompleteLinkage type that represents the complete linkage
 // bottom up cluster semantics
 type CompleteLinkage struct{}
 
@@ -18,42 +13,22 @@ func NewCompleteLinkage() *CompleteLinkage {
 	return &CompleteLinkage{}
 }
 
-// Swap swaps the first distance with the second distance
-// if the first distance is greater than the second one
-// If not it does viceversa
-func (c CompleteLinkage) Swap(first, second distance.Distance) {
-	for fc, f := range first.Points {
-		s, ok := second.Points[fc]
-		if !ok {
-			continue
-		}
-		first.Points[fc] = math.Max(f, s)
-	}
+var (
+	_ lancewilliams.Linkage   = (*CompleteLinkage)(nil)
+	_ lancewilliams.Reducible = (*CompleteLinkage)(nil)
+)
+
+// Coefficients returns the complete linkage coefficients
+// αi = αj = 1/2, β = 0 and γ = 1/2, this will always
+// keep the maximum of the two distances
+func (c CompleteLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5, Gamma: 0.5}
 }
 
-// Recompute recomputes the remaining distances after
-// the swap process is done based on the cluster provided and returns the best
-// distance alongside with the keys of the map of distances that should be removed
-func (c CompleteLinkage) Recompute(based set.Set, on distance.Distance) (float64, []set.Set) {
-	toBeDeleted := []set.Set{}
-	previous := set.NewSet()
-	best := -1.0
-	for cluster, distance := range on.Points {
-		if based.In(cluster) {
-			if best == -1.0 {
-				best = distance
-				previous = cluster
-				continue
-			}
-			if best > distance {
-				toBeDeleted = append(toBeDeleted, cluster)
-			} else {
-				best = distance
-				toBeDeleted = append(toBeDeleted, previous)
-				previous = cluster
-			}
-		}
-	}
+// Squared returns false, the complete linkage formula is
+// applied on the distances
+func (c CompleteLinkage) Squared() bool { return false }
 
-	return best, toBeDeleted
-}
+// Reducible returns true, the complete linkage satisfies
+// the reducibility property
+func (c CompleteLinkage) Reducible() bool { return true }
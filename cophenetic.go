@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"math"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// Cophenetic returns the cophenetic matrix of the dendrogram, the distance
+// between two leaves is the distance of the merge that first joined them
+// in the same cluster. The rows and the columns of the matrix follow the
+// order of the leaves, the leaves that were never joined are at distance 0.
+// If the dendrogram has no leaves this will return nil
+func (d Dendrogram) Cophenetic() *distance.Matrix {
+	n := len(d.Leaves)
+	if n == 0 {
+		return nil
+	}
+
+	cls := make([]set.IDs, n, n)
+	for i := range cls {
+		cls[i] = set.IDs{i}
+	}
+
+	// every merge sets the distance between all the
+	// leaves of the first cluster and the second one
+	m := distance.NewZeroMatrix(n)
+	for _, s := range d.steps() {
+		for _, a := range cls[s.first] {
+			for _, b := range cls[s.second] {
+				m.Set(a, b, s.distance)
+			}
+		}
+		cls[s.first].Add(cls[s.second])
+		cls[s.second] = nil
+	}
+
+	return m
+}
+
+// CopheneticCorrelation returns the cophenetic correlation coefficient,
+// the Pearson correlation between the distances of the matrix and
+// the cophenetic distances of the dendrogram. The closer the coefficient
+// is to 1 the better the dendrogram preserves the distances.
+// The matrix must follow the order of the leaves, same as the matrix
+// returned by distance.Condense for the table the dendrogram was fit on.
+// If the matrix does not match the leaves or the correlation is not
+// defined this will return NaN
+func (d Dendrogram) CopheneticCorrelation(m *distance.Matrix) float64 {
+	n := len(d.Leaves)
+	if m == nil || m.Len() != n || n < 2 {
+		return math.NaN()
+	}
+
+	cophenetic := d.Cophenetic()
+	pairs := float64(n * (n - 1) / 2)
+	meanX, meanY := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			meanX += m.At(i, j)
+			meanY += cophenetic.At(i, j)
+		}
+	}
+	meanX /= pairs
+	meanY /= pairs
+
+	sxy, sxx, syy := 0.0, 0.0, 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			x, y := m.At(i, j)-meanX, cophenetic.At(i, j)-meanY
+			sxy += x * y
+			sxx += x * x
+			syy += y * y
+		}
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+
+	return sxy / math.Sqrt(sxx*syy)
+}
@@ -0,0 +1,73 @@
+package cluster_test
+
+import (
+	"math"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/util"
+	gc "gopkg.in/check.v1"
+)
+
+type copheneticSuite struct{}
+
+var _ = gc.Suite(&copheneticSuite{})
+
+func (cs copheneticSuite) TestCophenetic(c *gc.C) {
+	distances := distance.NewDistances(one.NewDistances(0, 1, 3))
+	dendrogram := cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	cophenetic := dendrogram.Cophenetic()
+	c.Assert(cophenetic.Len(), gc.Equals, 3)
+	c.Assert(cophenetic.At(0, 1), gc.Equals, 1.0)
+	c.Assert(cophenetic.At(0, 2), gc.Equals, 2.0)
+	c.Assert(cophenetic.At(2, 1), gc.Equals, 2.0)
+
+	correlation := dendrogram.CopheneticCorrelation(distance.Condense(distances))
+	c.Assert(util.Round(correlation, 4), gc.Equals, 0.866)
+}
+
+func (cs copheneticSuite) TestCopheneticMerges(c *gc.C) {
+	distances := clusterSuite{}.twoDistances(c)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		dendrogram := cluster.FitDendrogram(distances, s)
+		cophenetic := dendrogram.Cophenetic()
+
+		// two leaves are at the distance of the first merge
+		// that puts them in the same cluster
+		for i := range dendrogram.Leaves {
+			for j := i + 1; j < len(dendrogram.Leaves); j++ {
+				a, b := dendrogram.Leaves[i], dendrogram.Leaves[j]
+				for _, merge := range dendrogram.Merges {
+					if merge.First.In(a) && merge.Second.In(b) || merge.First.In(b) && merge.Second.In(a) {
+						c.Assert(cophenetic.At(i, j), gc.Equals, merge.Distance)
+						break
+					}
+				}
+			}
+		}
+
+		correlation := dendrogram.CopheneticCorrelation(distance.Condense(distances))
+		c.Assert(correlation > 0 && correlation <= 1, gc.Equals, true)
+	}
+}
+
+func (cs copheneticSuite) TestCopheneticCorrelationUltrametric(c *gc.C) {
+	// the distances are already a hierarchy
+	distances := distance.NewDistances(one.NewDistances(0, 1, 3))
+	distances[0].Points["x3"] = 2
+	dendrogram := cluster.FitDendrogram(distances, cluster.AverageLinkage)
+	correlation := dendrogram.CopheneticCorrelation(distance.Condense(distances))
+	c.Assert(util.Round(correlation, 4), gc.Equals, 1.0)
+}
+
+func (cs copheneticSuite) TestCopheneticInvalid(c *gc.C) {
+	c.Assert(cluster.Dendrogram{}.Cophenetic(), gc.IsNil)
+
+	distances := clusterSuite{}.oneDistances(c)
+	dendrogram := cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	correlation := dendrogram.CopheneticCorrelation(distance.NewZeroMatrix(3))
+	c.Assert(math.IsNaN(correlation), gc.Equals, true)
+	correlation = dendrogram.CopheneticCorrelation(nil)
+	c.Assert(math.IsNaN(correlation), gc.Equals, true)
+}
@@ -0,0 +1,187 @@
+package cluster
+
+import (
+	"strings"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// Merge describes one step of the agglomeration, the moment
+// when two clusters are joined together
+type Merge struct {
+	// First is the cluster that absorbed the second one
+	First set.Set `json:"first"`
+	// Second is the cluster absorbed by the first one
+	Second set.Set `json:"second"`
+	// Distance is the linkage distance between the two clusters
+	// at the moment they were joined
+	Distance float64 `json:"distance"`
+	// Size is the number of points of the resulting cluster
+	Size int `json:"size"`
+	// Inversion reports that the clusters were joined at a lower distance
+	// than the previous merge. This can happen with any linkage that is not
+	// monotonic, like the CentroidLinkage and MedianLinkage strategies, the
+	// flexible beta linkage with a positive beta or a linkage used by FitWith
+	Inversion bool `json:"inversion,omitempty"`
+}
+
+// Dendrogram holds the full merge history of the clustering,
+// starting from every point in its own cluster and ending
+// with all the points in one cluster
+type Dendrogram struct {
+	// Leaves are the clusters of one point that the
+	// agglomeration starts from
+	Leaves []set.Set `json:"leaves"`
+	// Merges are all the merges in the order they were made
+	Merges []Merge `json:"merges"`
+}
+
+// FitDendrogram will fit the points based on the strategy of clustering
+// provided until one cluster remains and returns every merge made.
+// If the table of distances is empty this will return nil
+func FitDendrogram(points []distance.Distance, s strategy) *Dendrogram {
+	return FitDendrogramTies(points, s, LowestIndex())
+}
+
+// FitDendrogramTies will fit the points the same way FitDendrogram does,
+// choosing the pair of clusters merged with the tie break policy when
+// several pairs are at the same distance.
+// If the table of distances is empty this will return nil
+func FitDendrogramTies(points []distance.Distance, s strategy, ties TieBreak) *Dendrogram {
+	if len(points) == 0 {
+		return nil
+	}
+
+	linkage := newLinkage(s)
+	if linkage == nil {
+		return nil
+	}
+
+	cls := leaves(points)
+	steps, _ := hierarchy(distance.Condense(points), sizes(cls), linkage, ties, tracker{})
+	return &Dendrogram{
+		Leaves: cls,
+		Merges: merges(cls, steps),
+	}
+}
+
+// Monotonic returns true if every merge of the dendrogram
+// happened at a distance greater or equal than the previous one
+func (d Dendrogram) Monotonic() bool {
+	for _, merge := range d.Merges {
+		if merge.Inversion {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cut returns the k clusters of the dendrogram by replaying the
+// merges until only k clusters remain. The clusters are returned
+// in the same order Fit would return them
+// If k is not in the range of the leaves this will return nil
+func (d Dendrogram) Cut(k int) []set.Set {
+	n := len(d.Leaves)
+	if k <= 0 || k > n || n-k > len(d.Merges) {
+		return nil
+	}
+
+	return cut(d.Leaves, d.steps(), func(n int, _ float64) bool {
+		return n == k
+	})
+}
+
+// steps returns the merges of the dendrogram as steps between the
+// clusters of the leaves, the merges of unknown clusters are skipped
+func (d Dendrogram) steps() []step {
+	leaf := make(map[string]int, len(d.Leaves))
+	for i, cluster := range d.Leaves {
+		for _, point := range cluster.Slice() {
+			leaf[point] = i
+		}
+	}
+
+	// root holds for every leaf the leaf that
+	// represents its cluster at the current merge
+	root := make([]int, len(d.Leaves), len(d.Leaves))
+	for i := range root {
+		root[i] = i
+	}
+	find := func(i int) int {
+		for root[i] != i {
+			root[i] = root[root[i]]
+			i = root[i]
+		}
+		return i
+	}
+
+	steps := make([]step, 0, len(d.Merges))
+	for _, merge := range d.Merges {
+		a, ok := leaf[head(merge.First)]
+		b, found := leaf[head(merge.Second)]
+		if !ok || !found {
+			continue
+		}
+
+		i, j := find(a), find(b)
+		if i == j {
+			continue
+		}
+		root[j] = i
+		steps = append(steps, step{
+			first:     i,
+			second:    j,
+			distance:  merge.Distance,
+			size:      merge.Size,
+			inversion: merge.Inversion,
+		})
+	}
+
+	return steps
+}
+
+// head returns the first point of the cluster
+func head(cluster set.Set) string {
+	if i := strings.IndexByte(string(cluster), ','); i != -1 {
+		return string(cluster[:i])
+	}
+
+	return string(cluster)
+}
+
+// step describes a merge by the index of the clusters merged.
+// Every cluster is identified by the index of one of its leaves,
+// the cluster second is moved in the cluster first
+type step struct {
+	first     int
+	second    int
+	distance  float64
+	size      int
+	inversion bool
+}
+
+// merges returns the merges of the steps where
+// every cluster is named after its leaves
+func merges(leaves []set.Set, steps []step) []Merge {
+	cls := make([]set.IDs, len(leaves), len(leaves))
+	for i := range cls {
+		cls[i] = set.IDs{i}
+	}
+
+	merges := make([]Merge, 0, len(steps))
+	for _, s := range steps {
+		merges = append(merges, Merge{
+			First:     cls[s.first].Set(leaves),
+			Second:    cls[s.second].Set(leaves),
+			Distance:  s.distance,
+			Size:      s.size,
+			Inversion: s.inversion,
+		})
+		cls[s.first].Add(cls[s.second])
+		cls[s.second] = nil
+	}
+
+	return merges
+}
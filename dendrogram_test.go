@@ -0,0 +1,99 @@
+package cluster_test
+
+import (
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/two"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type dendrogramSuite struct{}
+
+var _ = gc.Suite(&dendrogramSuite{})
+
+func (ds dendrogramSuite) TestFitDendrogramEmpty(c *gc.C) {
+	d := cluster.FitDendrogram(nil, cluster.SingleLinkage)
+	c.Assert(d, gc.IsNil)
+
+	d = cluster.FitDendrogram([]distance.Distance{}, cluster.SingleLinkage)
+	c.Assert(d, gc.IsNil)
+}
+
+func (ds dendrogramSuite) TestFitDendrogramOneSingleLinkage(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	d := cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	c.Assert(d, gc.NotNil)
+	c.Assert(d.Leaves, gc.DeepEquals, []set.Set{
+		"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8",
+	})
+	c.Assert(d.Merges, gc.DeepEquals, []cluster.Merge{
+		{First: "x2", Second: "x3", Distance: 0.1, Size: 2},
+		{First: "x5", Second: "x6", Distance: 0.1, Size: 2},
+		{First: "x7", Second: "x8", Distance: 0.1, Size: 2},
+		{First: "x2,x3", Second: "x4", Distance: 0.2, Size: 3},
+		{First: "x5,x6", Second: "x7,x8", Distance: 0.2, Size: 4},
+		{First: "x1", Second: "x2,x3,x4", Distance: 0.4, Size: 4},
+		{First: "x1,x2,x3,x4", Second: "x5,x6,x7,x8", Distance: 1.2, Size: 8},
+	})
+}
+
+func (ds dendrogramSuite) TestDendrogramCut(c *gc.C) {
+	for _, distances := range [][]distance.Distance{
+		clusterSuite{}.oneDistances(c),
+		clusterSuite{}.twoDistances(c),
+	} {
+		for _, d := range []*cluster.Dendrogram{
+			cluster.FitDendrogram(distances, cluster.SingleLinkage),
+			cluster.FitDendrogram(distances, cluster.CompleteLinkage),
+			cluster.FitDendrogram(distances, cluster.AverageLinkage),
+		} {
+			c.Assert(d, gc.NotNil)
+			c.Assert(d.Merges, gc.HasLen, len(distances)-1)
+			c.Assert(d.Cut(0), gc.IsNil)
+			c.Assert(d.Cut(len(distances)+1), gc.IsNil)
+			c.Assert(d.Cut(len(distances)), gc.DeepEquals, d.Leaves)
+			c.Assert(d.Cut(1), gc.HasLen, 1)
+		}
+	}
+}
+
+func (ds dendrogramSuite) TestDendrogramCutMatchesFit(c *gc.C) {
+	distances := clusterSuite{}.twoDistances(c)
+	d := cluster.FitDendrogram(distances, cluster.CompleteLinkage)
+	c.Assert(d, gc.NotNil)
+	for k := len(distances); k > 0; k-- {
+		c.Assert(d.Cut(k), gc.DeepEquals, cluster.Fit(distances, cluster.CompleteLinkage, k))
+	}
+
+	distances = clusterSuite{}.oneDistances(c)
+	d = cluster.FitDendrogram(distances, cluster.AverageLinkage)
+	c.Assert(d, gc.NotNil)
+	for k := len(distances); k > 0; k-- {
+		c.Assert(d.Cut(k), gc.DeepEquals, cluster.Fit(distances, cluster.AverageLinkage, k))
+	}
+}
+
+func (ds dendrogramSuite) TestFitDendrogramInversion(c *gc.C) {
+	points := two.NewDistances(
+		[]float64{0, 2, 1},
+		[]float64{0, 0, 1.8},
+	)
+	distances := distance.NewDistances(points)
+
+	d := cluster.FitDendrogram(distances, cluster.CentroidLinkage)
+	c.Assert(d, gc.NotNil)
+	c.Assert(d.Monotonic(), gc.Equals, false)
+	c.Assert(d.Merges, gc.DeepEquals, []cluster.Merge{
+		{First: "x1", Second: "x2", Distance: 2, Size: 2},
+		{First: "x1,x2", Second: "x3", Distance: 1.8, Size: 3, Inversion: true},
+	})
+
+	d = cluster.FitDendrogram(distances, cluster.MedianLinkage)
+	c.Assert(d, gc.NotNil)
+	c.Assert(d.Monotonic(), gc.Equals, false)
+
+	d = cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	c.Assert(d, gc.NotNil)
+	c.Assert(d.Monotonic(), gc.Equals, true)
+}
@@ -10,16 +10,24 @@ import (
 	"github.com/hoenirvili/cluster/set"
 )
 
+// prefix is the prefix of every point name
+const prefix = "x"
+
+// Name returns the name of the i-th point, the points
+// are named x1, x2, x3, ... in the order they are given
+func Name(i int) set.Set {
+	return set.NewSet(fmt.Sprintf("%s%d", prefix, i+1))
+}
+
 // NewDistances returns a table of cluster distances
 func NewDistances(points []dimension.Distancer) []Distance {
-	prefix := "x"
 	n := len(points)
 	distances := []Distance{}
 
 	for i := 0; i < n; i++ {
 		// create a new fixed cluster
 		distance := Distance{
-			Set: set.NewSet(fmt.Sprintf("%s%d", prefix, i+1)),
+			Set: Name(i),
 		}
 
 		// if we reached the end of the list
@@ -36,7 +44,7 @@ func NewDistances(points []dimension.Distancer) []Distance {
 			// length is the distance between cluster i and j
 			length := points[i].Distance(points[j])
 			// create the cluster name  for j
-			key := set.NewSet(fmt.Sprintf("%s%d", prefix, j+1))
+			key := Name(j)
 			// assign distance to the map
 			distance.Points[key] = length
 		}
@@ -81,7 +89,9 @@ func (d *Distance) Merge(c set.Set) {
 }
 
 // Best picks a pair of clusters and minimum distance
-// of the hole row of distances
+// of the hole row of distances. If several clusters are at
+// the minimum distance the one that comes first by set.Priority
+// is picked, no matter the order of the map
 // If row does not contain distance points it will return
 // an empty pair and 0.0
 func (d Distance) Best() (set.Set, set.Set, float64) {
@@ -119,6 +119,11 @@ func (d distanceSuite) TestNewDistances(c *gc.C) {
 	}
 }
 
+func (d distanceSuite) TestName(c *gc.C) {
+	c.Assert(distance.Name(0), gc.Equals, set.Set("x1"))
+	c.Assert(distance.Name(9), gc.Equals, set.Set("x10"))
+}
+
 func (d distanceSuite) TestDistanceBest(c *gc.C) {
 	distances := d.distances(c)
 	pair := [][]set.Set{
@@ -145,6 +150,28 @@ func (d distanceSuite) TestDistanceBest(c *gc.C) {
 	}
 }
 
+func (d distanceSuite) TestDistanceBestTies(c *gc.C) {
+	row := distance.Distance{
+		Set: "x1",
+		Points: map[set.Set]float64{
+			"x4,x5":   0.5,
+			"x2,x7":   0.5,
+			"x2,x3":   0.5,
+			"x6":      0.5,
+			"x8,x9":   0.7,
+			"x10,x11": 0.5,
+		},
+	}
+
+	// the map order changes on every run
+	for i := 0; i < 20; i++ {
+		first, second, d := row.Best()
+		c.Assert(first, gc.Equals, set.Set("x1,x2,x3"))
+		c.Assert(second, gc.Equals, set.Set("x2,x3"))
+		c.Assert(d, gc.Equals, 0.5)
+	}
+}
+
 func (d distanceSuite) TestDistanceMerge(c *gc.C) {
 	distance := d.distances(c)[0]
 
@@ -0,0 +1,105 @@
+package distance
+
+import (
+	"github.com/hoenirvili/cluster/dimension"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// Matrix holds the distances between every pair of n points.
+// The matrix is symmetric with zero on the diagonal so only the upper
+// triangle is stored, row by row, in a flat slice of n*(n-1)/2 distances
+type Matrix struct {
+	n         int
+	distances []float64
+}
+
+// NewMatrix returns the matrix of distances between every pair of points
+func NewMatrix(points []dimension.Distancer) *Matrix {
+	m := NewZeroMatrix(len(points))
+	for i := 0; i < m.n; i++ {
+		for j := i + 1; j < m.n; j++ {
+			m.distances[m.index(i, j)] = points[i].Distance(points[j])
+		}
+	}
+
+	return m
+}
+
+// Condense returns the matrix of distances of the table, the rows
+// and the columns of the matrix follow the order of the rows in the table
+func Condense(table []Distance) *Matrix {
+	m := NewZeroMatrix(len(table))
+	index := make(map[set.Set]int, len(table))
+	for i, row := range table {
+		index[row.Set] = i
+	}
+
+	for i, row := range table {
+		for cluster, d := range row.Points {
+			j, ok := index[cluster]
+			if !ok || i == j {
+				continue
+			}
+			m.Set(i, j, d)
+		}
+	}
+
+	return m
+}
+
+// NewZeroMatrix returns the matrix of n points with every distance zero
+func NewZeroMatrix(n int) *Matrix {
+	size := 0
+	if n > 1 {
+		size = n * (n - 1) / 2
+	}
+
+	return &Matrix{
+		n:         n,
+		distances: make([]float64, size, size),
+	}
+}
+
+// index returns the position of the distance between i and j
+// in the flat slice, i must be lower than j
+func (m Matrix) index(i, j int) int {
+	return i*(2*m.n-i-1)/2 + j - i - 1
+}
+
+// Len returns the number of points of the matrix
+func (m Matrix) Len() int {
+	return m.n
+}
+
+// At returns the distance between the points i and j
+func (m Matrix) At(i, j int) float64 {
+	switch {
+	case i == j:
+		return 0
+	case j < i:
+		i, j = j, i
+	}
+
+	return m.distances[m.index(i, j)]
+}
+
+// Set sets the distance between the points i and j.
+// The distance of a point to itself is always zero so
+// setting the diagonal has no effect
+func (m *Matrix) Set(i, j int, d float64) {
+	switch {
+	case i == j:
+		return
+	case j < i:
+		i, j = j, i
+	}
+
+	m.distances[m.index(i, j)] = d
+}
+
+// Copy returns a copy of the matrix
+func (m Matrix) Copy() *Matrix {
+	c := NewZeroMatrix(m.n)
+	copy(c.distances, m.distances)
+	return c
+}
@@ -0,0 +1,63 @@
+package distance_test
+
+import (
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	gc "gopkg.in/check.v1"
+)
+
+type matrixSuite struct{}
+
+var _ = gc.Suite(&matrixSuite{})
+
+func (m matrixSuite) TestNewMatrix(c *gc.C) {
+	points := one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0)
+	matrix := distance.NewMatrix(points)
+	c.Assert(matrix, gc.NotNil)
+	c.Assert(matrix.Len(), gc.Equals, len(points))
+	for i := range points {
+		for j := range points {
+			c.Assert(matrix.At(i, j), gc.Equals, points[i].Distance(points[j]))
+		}
+	}
+}
+
+func (m matrixSuite) TestCondense(c *gc.C) {
+	points := one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0)
+	expected := distance.NewMatrix(points)
+	matrix := distance.Condense(distance.NewDistances(points))
+	c.Assert(matrix, gc.DeepEquals, expected)
+}
+
+func (m matrixSuite) TestMatrixSet(c *gc.C) {
+	matrix := distance.NewMatrix(one.NewDistances(1, 2, 4))
+	matrix.Set(2, 0, 5)
+	c.Assert(matrix.At(0, 2), gc.Equals, 5.0)
+	c.Assert(matrix.At(2, 0), gc.Equals, 5.0)
+
+	// the diagonal is always zero
+	matrix.Set(1, 1, 5)
+	c.Assert(matrix.At(1, 1), gc.Equals, 0.0)
+
+	// the copy does not share the distances
+	copied := matrix.Copy()
+	copied.Set(0, 1, 7)
+	c.Assert(matrix.At(0, 1), gc.Equals, 1.0)
+	c.Assert(copied.At(0, 1), gc.Equals, 7.0)
+}
+
+func (m matrixSuite) TestEmptyMatrix(c *gc.C) {
+	c.Assert(distance.NewMatrix(nil).Len(), gc.Equals, 0)
+	c.Assert(distance.NewMatrix(one.NewDistances(1)).Len(), gc.Equals, 1)
+}
+
+func (m matrixSuite) TestNewZeroMatrix(c *gc.C) {
+	matrix := distance.NewZeroMatrix(3)
+	c.Assert(matrix.Len(), gc.Equals, 3)
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			c.Assert(matrix.At(i, j), gc.Equals, 0.0)
+		}
+	}
+	c.Assert(distance.NewZeroMatrix(0).Len(), gc.Equals, 0)
+}
@@ -0,0 +1,76 @@
+package distance
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/hoenirvili/cluster/dimension"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// NewDistancesParallel returns the same table of cluster distances as
+// NewDistances does, computing the rows concurrently with the given number
+// of workers. If workers is not positive it will use one worker for every
+// CPU. The points must be safe to be used by multiple goroutines
+func NewDistancesParallel(points []dimension.Distancer, workers int) []Distance {
+	n := len(points)
+	distances := make([]Distance, n, n)
+	rows(n, workers, func(i int) {
+		distances[i].Set = Name(i)
+		if i+1 == n {
+			return
+		}
+
+		distances[i].Points = make(map[set.Set]float64, n-i-1)
+		for j := i + 1; j < n; j++ {
+			distances[i].Points[Name(j)] = points[i].Distance(points[j])
+		}
+	})
+
+	return distances
+}
+
+// NewMatrixParallel returns the same matrix of distances as NewMatrix
+// does, computing the rows concurrently with the given number of workers.
+// If workers is not positive it will use one worker for every CPU.
+// The points must be safe to be used by multiple goroutines
+func NewMatrixParallel(points []dimension.Distancer, workers int) *Matrix {
+	m := NewZeroMatrix(len(points))
+	rows(m.n, workers, func(i int) {
+		for j := i + 1; j < m.n; j++ {
+			m.distances[m.index(i, j)] = points[i].Distance(points[j])
+		}
+	})
+
+	return m
+}
+
+// rows calls row for every row index from 0 to n-1 using the
+// given number of workers and waits until all rows are done.
+// Every row is handled by exactly one worker
+func rows(n, workers int, row func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				row(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+}
@@ -0,0 +1,72 @@
+package distance_test
+
+import (
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/hoenirvili/cluster/dimension"
+	"github.com/hoenirvili/cluster/dimension/two"
+	"github.com/hoenirvili/cluster/distance"
+	gc "gopkg.in/check.v1"
+)
+
+type parallelSuite struct{}
+
+var _ = gc.Suite(&parallelSuite{})
+
+func (p parallelSuite) points(n int) []dimension.Distancer {
+	r := rand.New(rand.NewSource(1))
+	x, y := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = float64(r.Intn(100000)) / 100
+		y[i] = float64(r.Intn(100000)) / 100
+	}
+
+	return two.NewDistances(x, y)
+}
+
+// counter counts every distance computed
+type counter struct {
+	dimension.Distancer
+	calls *int64
+}
+
+func (cr counter) Distance(p dimension.Point) float64 {
+	atomic.AddInt64(cr.calls, 1)
+	return cr.Distancer.Distance(p)
+}
+
+func (p parallelSuite) TestNewDistancesParallel(c *gc.C) {
+	points := p.points(50)
+	expected := distance.NewDistances(points)
+	for _, workers := range []int{-1, 0, 1, 3, 8, 100} {
+		distances := distance.NewDistancesParallel(points, workers)
+		c.Assert(distances, gc.DeepEquals, expected)
+	}
+}
+
+func (p parallelSuite) TestNewMatrixParallel(c *gc.C) {
+	points := p.points(50)
+	expected := distance.NewMatrix(points)
+	for _, workers := range []int{-1, 0, 1, 3, 8, 100} {
+		matrix := distance.NewMatrixParallel(points, workers)
+		c.Assert(matrix, gc.DeepEquals, expected)
+	}
+}
+
+func (p parallelSuite) TestParallelEveryPairOnce(c *gc.C) {
+	calls := int64(0)
+	points := p.points(20)
+	for i := range points {
+		points[i] = counter{Distancer: points[i], calls: &calls}
+	}
+
+	distance.NewDistancesParallel(points, 4)
+	c.Assert(calls, gc.Equals, int64(20*19/2))
+}
+
+func (p parallelSuite) TestParallelEmpty(c *gc.C) {
+	c.Assert(distance.NewDistancesParallel(nil, 4), gc.HasLen, 0)
+	c.Assert(distance.NewMatrixParallel(nil, 4).Len(), gc.Equals, 0)
+	c.Assert(distance.NewDistancesParallel(p.points(1), 4), gc.DeepEquals, distance.NewDistances(p.points(1)))
+}
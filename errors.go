@@ -0,0 +1,143 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/hoenirvili/cluster/set"
+)
+
+// InvalidKError is returned when the number of clusters
+// requested is not in the range of the points
+type InvalidKError struct {
+	// K is the number of clusters requested
+	K int
+	// N is the number of points
+	N int
+}
+
+// Error returns the error message
+func (e InvalidKError) Error() string {
+	return fmt.Sprintf("cluster: invalid number of clusters %d, expected a value between 1 and %d", e.K, e.N)
+}
+
+// UnknownStrategyError is returned when the
+// strategy of clustering is not known
+type UnknownStrategyError struct {
+	// Strategy is the value of the strategy provided
+	Strategy uint8
+}
+
+// Error returns the error message
+func (e UnknownStrategyError) Error() string {
+	return fmt.Sprintf("cluster: unknown strategy %d", e.Strategy)
+}
+
+// EmptyTableError is returned when the table of distances has no rows
+type EmptyTableError struct{}
+
+// Error returns the error message
+func (e EmptyTableError) Error() string {
+	return "cluster: empty table of distances"
+}
+
+// RaggedTableError is returned when the distance between two
+// clusters of the table is missing or when the table holds a distance
+// to a cluster that has no row
+type RaggedTableError struct {
+	// First is the cluster of the row
+	First set.Set
+	// Second is the cluster that has a missing or unknown distance
+	Second set.Set
+	// Unknown reports that the second cluster has no row in the table
+	Unknown bool
+}
+
+// Error returns the error message
+func (e RaggedTableError) Error() string {
+	if e.Unknown {
+		return fmt.Sprintf("cluster: ragged table, %s holds a distance to the unknown cluster %s", e.First, e.Second)
+	}
+
+	return fmt.Sprintf("cluster: ragged table, missing distance between %s and %s", e.First, e.Second)
+}
+
+// InvalidDistanceError is returned when the distance
+// between two clusters is NaN or negative
+type InvalidDistanceError struct {
+	// First is the cluster of the row
+	First set.Set
+	// Second is the cluster of the column
+	Second set.Set
+	// Distance is the invalid distance
+	Distance float64
+}
+
+// Error returns the error message
+func (e InvalidDistanceError) Error() string {
+	return fmt.Sprintf("cluster: invalid distance %v between %s and %s", e.Distance, e.First, e.Second)
+}
+
+// DuplicateSetError is returned when two rows of the table of
+// distances have the same cluster or clusters sharing a point
+type DuplicateSetError struct {
+	// Set is the duplicated cluster
+	Set set.Set
+}
+
+// Error returns the error message
+func (e DuplicateSetError) Error() string {
+	return fmt.Sprintf("cluster: duplicate cluster %s", e.Set)
+}
+
+// InvalidSetError is returned when a cluster of the table
+// does not follow the naming convention of the set package
+type InvalidSetError struct {
+	// Set is the invalid cluster
+	Set set.Set
+}
+
+// Error returns the error message
+func (e InvalidSetError) Error() string {
+	return fmt.Sprintf("cluster: invalid cluster name %q", string(e.Set))
+}
+
+// NewickError is returned when a tree in the Newick format can not be read
+type NewickError struct {
+	// Offset is the byte offset in the text where the error was found
+	Offset int
+	// Reason describes what is wrong with the tree
+	Reason string
+}
+
+// Error returns the error message
+func (e NewickError) Error() string {
+	return fmt.Sprintf("cluster: invalid newick tree at offset %d, %s", e.Offset, e.Reason)
+}
+
+// InvalidMergeError is returned when a merge of a hierarchy does not join
+// two clusters that are not merged yet or its size does not match them
+type InvalidMergeError struct {
+	// Index is the index of the merge
+	Index int
+	// Merge is the invalid merge
+	Merge Merge
+}
+
+// Error returns the error message
+func (e InvalidMergeError) Error() string {
+	return fmt.Sprintf("cluster: invalid merge %d of %s and %s", e.Index, e.Merge.First, e.Merge.Second)
+}
+
+// LinkageMatrixError is returned when a row of a linkage matrix
+// does not describe a merge of two clusters not merged yet
+type LinkageMatrixError struct {
+	// Row is the index of the row
+	Row int
+	// Reason describes what is wrong with the row
+	Reason string
+}
+
+// Error returns the error message
+func (e LinkageMatrixError) Error() string {
+	return fmt.Sprintf("cluster: invalid linkage matrix at row %d, %s", e.Row, e.Reason)
+}
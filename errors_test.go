@@ -0,0 +1,68 @@
+package cluster_test
+
+import (
+	"regexp"
+
+	"github.com/hoenirvili/cluster"
+	gc "gopkg.in/check.v1"
+)
+
+type errorsSuite struct{}
+
+var _ = gc.Suite(&errorsSuite{})
+
+func (e errorsSuite) TestErrors(c *gc.C) {
+	tests := []struct {
+		err     error
+		message string
+	}{
+		{
+			cluster.InvalidKError{K: 0, N: 3},
+			"cluster: invalid number of clusters 0, expected a value between 1 and 3",
+		},
+		{
+			cluster.UnknownStrategyError{Strategy: 9},
+			"cluster: unknown strategy 9",
+		},
+		{
+			cluster.EmptyTableError{},
+			"cluster: empty table of distances",
+		},
+		{
+			cluster.RaggedTableError{First: "x1", Second: "x2"},
+			"cluster: ragged table, missing distance between {x1} and {x2}",
+		},
+		{
+			cluster.RaggedTableError{First: "x1", Second: "x9", Unknown: true},
+			"cluster: ragged table, {x1} holds a distance to the unknown cluster {x9}",
+		},
+		{
+			cluster.InvalidDistanceError{First: "x1", Second: "x2", Distance: -0.5},
+			"cluster: invalid distance -0.5 between {x1} and {x2}",
+		},
+		{
+			cluster.DuplicateSetError{Set: "x1"},
+			"cluster: duplicate cluster {x1}",
+		},
+		{
+			cluster.InvalidSetError{Set: "point"},
+			`cluster: invalid cluster name "point"`,
+		},
+		{
+			cluster.NewickError{Offset: 4, Reason: "expected ;"},
+			"cluster: invalid newick tree at offset 4, expected ;",
+		},
+		{
+			cluster.InvalidMergeError{Index: 2, Merge: cluster.Merge{First: "x1", Second: "x3"}},
+			"cluster: invalid merge 2 of {x1} and {x3}",
+		},
+		{
+			cluster.LinkageMatrixError{Row: 1, Reason: "unknown cluster 7"},
+			"cluster: invalid linkage matrix at row 1, unknown cluster 7",
+		},
+	}
+
+	for _, test := range tests {
+		c.Assert(test.err, gc.ErrorMatches, regexp.QuoteMeta(test.message))
+	}
+}
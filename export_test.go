@@ -0,0 +1,35 @@
+package cluster
+
+import "github.com/hoenirvili/cluster/distance"
+
+// NNChainDendrogram returns the merges of the nearest neighbor chain
+// algorithm and false if it cannot guarantee the merges of the greedy one
+func NNChainDendrogram(points []distance.Distance, linkage Linkage) (*Dendrogram, bool) {
+	cls := leaves(points)
+	steps, ok, _ := nnchain(distance.Condense(points), sizes(cls), linkage, tracker{})
+	if !ok {
+		return nil, false
+	}
+
+	return &Dendrogram{Leaves: cls, Merges: merges(cls, steps)}, true
+}
+
+// GreedyDendrogram returns the merges of the greedy
+// algorithm with the LowestIndex policy
+func GreedyDendrogram(points []distance.Distance, linkage Linkage) *Dendrogram {
+	cls := leaves(points)
+	steps, _ := greedy(distance.Condense(points), sizes(cls), linkage, nil, tracker{})
+	return &Dendrogram{Leaves: cls, Merges: merges(cls, steps)}
+}
+
+// MSTDendrogram returns the single linkage merges of the minimum spanning
+// tree and false if it cannot guarantee the merges of the greedy algorithm
+func MSTDendrogram(points []distance.Distance) (*Dendrogram, bool) {
+	cls := leaves(points)
+	steps, ok, _ := mst(distance.Condense(points), sizes(cls), tracker{})
+	if !ok {
+		return nil, false
+	}
+
+	return &Dendrogram{Leaves: cls, Merges: merges(cls, steps)}, true
+}
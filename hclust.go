@@ -0,0 +1,129 @@
+package cluster
+
+import (
+	"fmt"
+	"math"
+)
+
+// HClust holds the merges of the dendrogram in the convention of the
+// hclust objects of R, with the same names when it is stored as json
+type HClust struct {
+	// Merge holds the two clusters merged by every merge. A negative value
+	// -j is the leaf j and a positive value j is the cluster of the merge j,
+	// counting from 1. The leaves come first and two leaves or two clusters
+	// are in increasing order
+	Merge [][2]int `json:"merge"`
+	// Height holds the distance of every merge
+	Height []float64 `json:"height"`
+	// Order is the order of the leaves, counting from 1,
+	// where the branches of the dendrogram do not cross
+	Order []int `json:"order"`
+	// Labels are the labels of the leaves
+	Labels []string `json:"labels,omitempty"`
+}
+
+// HClust returns the merges of the dendrogram in the convention of R, the
+// leaves are named with the labels, in the order of the leaves, or after
+// the leaves of the dendrogram if there are no labels.
+// If the dendrogram is not complete or the labels do not
+// match the leaves this will return an error
+func (d Dendrogram) HClust(labels []string) (*HClust, error) {
+	z, err := d.LinkageMatrix()
+	if err != nil {
+		return nil, err
+	}
+	labels, err = d.labels(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(d.Leaves)
+	h := &HClust{
+		Merge:  make([][2]int, len(z), len(z)),
+		Height: make([]float64, len(z), len(z)),
+		Order:  make([]int, 0, n),
+		Labels: labels,
+	}
+	for i, row := range z {
+		a, b := int(row[0]), int(row[1])
+		switch {
+		case a < n && b < n:
+			h.Merge[i] = [2]int{-(a + 1), -(b + 1)}
+		case a < n:
+			h.Merge[i] = [2]int{-(a + 1), b - n + 1}
+		default:
+			h.Merge[i] = [2]int{a - n + 1, b - n + 1}
+		}
+		h.Height[i] = row[2]
+	}
+
+	// the order expands every merge from the last one,
+	// the first cluster of the merge before the second one
+	stack := []int{len(z)}
+	for len(stack) > 0 {
+		j := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if j < 0 {
+			h.Order = append(h.Order, -j)
+			continue
+		}
+		if j == 0 {
+			// a dendrogram of one leaf
+			h.Order = append(h.Order, 1)
+			continue
+		}
+		stack = append(stack, h.Merge[j-1][1], h.Merge[j-1][0])
+	}
+
+	return h, nil
+}
+
+// FromHClust returns the dendrogram of the merges in the convention of R
+// and the labels of its leaves, in the order of the leaves, or nil if the
+// merges have no labels. The n leaves are named x1, x2, x3, ... same as
+// distance.NewDistances names them in the order of the leaves of the merges.
+// The order is not read.
+// If a merge does not join two clusters not merged yet this will
+// return a LinkageMatrixError, if the heights or the labels do not
+// match the merges this will return an error
+func FromHClust(h HClust) (*Dendrogram, []string, error) {
+	n := len(h.Merge) + 1
+	if len(h.Height) != len(h.Merge) {
+		return nil, nil, fmt.Errorf("cluster: %d heights for %d merges", len(h.Height), len(h.Merge))
+	}
+	if len(h.Labels) != 0 && len(h.Labels) != n {
+		return nil, nil, fmt.Errorf("cluster: %d labels for %d leaves", len(h.Labels), n)
+	}
+
+	// every merge is turned in a row of the linkage matrix, the number
+	// of points of the clusters that are not known is left to be checked
+	size := make([]float64, n-1, n-1)
+	z := make([][4]float64, len(h.Merge), len(h.Merge))
+	for i, merge := range h.Merge {
+		for c, j := range merge {
+			switch {
+			case j < 0:
+				z[i][c] = float64(-j - 1)
+				size[i]++
+			case j > 0:
+				z[i][c] = float64(n + j - 1)
+				if j <= i {
+					size[i] += size[j-1]
+				}
+			default:
+				z[i][c] = math.NaN()
+			}
+		}
+		z[i][2], z[i][3] = h.Height[i], size[i]
+	}
+
+	d, err := FromLinkageMatrix(z)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(h.Labels) == 0 {
+		return d, nil, nil
+	}
+
+	return d, append([]string(nil), h.Labels...), nil
+}
@@ -0,0 +1,92 @@
+package cluster_test
+
+import (
+	"encoding/json"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	gc "gopkg.in/check.v1"
+)
+
+type hclustSuite struct{}
+
+var _ = gc.Suite(&hclustSuite{})
+
+func (h hclustSuite) TestHClust(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	d := cluster.FitDendrogram(points, cluster.SingleLinkage)
+	hc, err := d.HClust([]string{"a", "b", "c", "d"})
+	c.Assert(err, gc.IsNil)
+	// the same merge, height and order hclust returns
+	c.Assert(hc, gc.DeepEquals, &cluster.HClust{
+		Merge:  [][2]int{{-1, -2}, {-3, 1}, {-4, 2}},
+		Height: []float64{1, 2, 4},
+		Order:  []int{4, 3, 1, 2},
+		Labels: []string{"a", "b", "c", "d"},
+	})
+
+	data, err := json.Marshal(hc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, `{"merge":[[-1,-2],[-3,1],[-4,2]],"height":[1,2,4],`+
+		`"order":[4,3,1,2],"labels":["a","b","c","d"]}`)
+
+	_, err = d.HClust([]string{"a"})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 4 leaves")
+}
+
+func (h hclustSuite) TestHClustRoundTrip(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0, 0.9, 3))
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		d := cluster.FitDendrogram(points, s)
+		hc, err := d.HClust(nil)
+		c.Assert(err, gc.IsNil)
+		c.Assert(hc.Order, gc.HasLen, len(points))
+
+		read, labels, err := cluster.FromHClust(*hc)
+		c.Assert(err, gc.IsNil)
+		c.Assert(read, gc.DeepEquals, d)
+		c.Assert(labels, gc.DeepEquals, hc.Labels)
+	}
+
+	// the labels written come back with the dendrogram
+	d := cluster.FitDendrogram(distance.NewDistances(one.NewDistances(0, 1, 3)), cluster.SingleLinkage)
+	hc, err := d.HClust([]string{"a", "b", "c"})
+	c.Assert(err, gc.IsNil)
+	read, labels, err := cluster.FromHClust(*hc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(read, gc.DeepEquals, d)
+	c.Assert(labels, gc.DeepEquals, []string{"a", "b", "c"})
+
+	hc.Labels = nil
+	_, labels, err = cluster.FromHClust(*hc)
+	c.Assert(err, gc.IsNil)
+	c.Assert(labels, gc.IsNil)
+}
+
+func (h hclustSuite) TestFromHClustErrors(c *gc.C) {
+	_, _, err := cluster.FromHClust(cluster.HClust{
+		Merge:  [][2]int{{-1, -2}, {-3, 1}},
+		Height: []float64{1},
+	})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 heights for 2 merges")
+
+	_, _, err = cluster.FromHClust(cluster.HClust{
+		Merge:  [][2]int{{-1, -2}},
+		Height: []float64{1},
+		Labels: []string{"a"},
+	})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 2 leaves")
+
+	_, _, err = cluster.FromHClust(cluster.HClust{
+		Merge:  [][2]int{{-1, -2}, {-3, 2}},
+		Height: []float64{1, 2},
+	})
+	c.Assert(err, gc.ErrorMatches, "cluster: invalid linkage matrix at row 1, unknown cluster 4")
+
+	_, _, err = cluster.FromHClust(cluster.HClust{
+		Merge:  [][2]int{{-1, -2}, {-1, 1}},
+		Height: []float64{1, 2},
+	})
+	c.Assert(err, gc.ErrorMatches, "cluster: invalid linkage matrix at row 1, cluster 0 is already merged")
+}
@@ -0,0 +1,171 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// Inconsistency holds the statistics of the distances of one merge
+// and of the merges below it, down to a given depth
+type Inconsistency struct {
+	// Mean is the mean of the distances of the merges
+	Mean float64
+	// Deviation is the standard deviation of the distances of the merges
+	Deviation float64
+	// Count is the number of merges
+	Count int
+	// Coefficient is the inconsistency coefficient of the merge,
+	// the distance of the merge minus the mean divided by the deviation.
+	// If the deviation is zero the coefficient is zero
+	Coefficient float64
+}
+
+// Inconsistency returns the inconsistency statistics of every merge,
+// in the order of the merges. The statistics of a merge are computed
+// from its distance and the distances of the merges below it, down to
+// depth levels, the merge itself is on the first level.
+// If depth is not positive this will return nil
+func (d Dendrogram) Inconsistency(depth int) []Inconsistency {
+	if depth <= 0 {
+		return nil
+	}
+
+	steps := d.steps()
+	children := d.children(steps)
+	stats := make([]Inconsistency, len(steps), len(steps))
+	for i, s := range steps {
+		distances := make([]float64, 0)
+		var below func(merge, level int)
+		below = func(merge, level int) {
+			distances = append(distances, steps[merge].distance)
+			if level == depth {
+				return
+			}
+			for _, child := range children[merge] {
+				if child != -1 {
+					below(child, level+1)
+				}
+			}
+		}
+		below(i, 1)
+
+		mean := 0.0
+		for _, distance := range distances {
+			mean += distance
+		}
+		mean /= float64(len(distances))
+
+		deviation := 0.0
+		if len(distances) > 1 {
+			for _, distance := range distances {
+				deviation += (distance - mean) * (distance - mean)
+			}
+			deviation = math.Sqrt(deviation / float64(len(distances)-1))
+		}
+
+		stats[i] = Inconsistency{
+			Mean:      mean,
+			Deviation: deviation,
+			Count:     len(distances),
+		}
+		if deviation > 0 {
+			stats[i].Coefficient = (s.distance - mean) / deviation
+		}
+	}
+
+	return stats
+}
+
+// children returns for every step the index of the steps
+// that created the two clusters merged, or -1 for a leaf
+func (d Dendrogram) children(steps []step) [][2]int {
+	// last holds for every cluster the step that created it
+	last := make([]int, len(d.Leaves), len(d.Leaves))
+	for i := range last {
+		last[i] = -1
+	}
+
+	children := make([][2]int, len(steps), len(steps))
+	for i, s := range steps {
+		children[i] = [2]int{last[s.first], last[s.second]}
+		last[s.first], last[s.second] = i, -1
+	}
+
+	return children
+}
+
+// CutInconsistent returns the clusters of the dendrogram where every
+// cluster is formed by a merge that has, together with all the merges
+// below it, an inconsistency coefficient lower or equal than the threshold.
+// The coefficients are computed with the given depth, see Inconsistency.
+// The clusters are returned in the order of their first leaf.
+// If depth is not positive or the threshold is NaN this will return nil
+func (d Dendrogram) CutInconsistent(threshold float64, depth int) []set.Set {
+	if depth <= 0 || math.IsNaN(threshold) || len(d.Leaves) == 0 {
+		return nil
+	}
+
+	steps := d.steps()
+	children := d.children(steps)
+	stats := d.Inconsistency(depth)
+
+	// a merge is applied if it is consistent and all the merges below
+	// it are applied, the merges below come always first
+	applied := make([]bool, len(steps), len(steps))
+	cls := make([]set.IDs, len(d.Leaves), len(d.Leaves))
+	for i := range cls {
+		cls[i] = set.IDs{i}
+	}
+	for i, s := range steps {
+		if stats[i].Coefficient > threshold {
+			continue
+		}
+		consistent := true
+		for _, child := range children[i] {
+			if child != -1 && !applied[child] {
+				consistent = false
+			}
+		}
+		if !consistent {
+			continue
+		}
+
+		applied[i] = true
+		cls[s.first].Add(cls[s.second])
+		cls[s.second] = nil
+	}
+
+	remaining := make([]set.IDs, 0, len(cls))
+	for _, c := range cls {
+		if c != nil {
+			remaining = append(remaining, c)
+		}
+	}
+	sort.Slice(remaining, func(i, j int) bool {
+		return remaining[i].Less(remaining[j])
+	})
+
+	flat := make([]set.Set, 0, len(remaining))
+	for _, c := range remaining {
+		flat = append(flat, c.Set(d.Leaves))
+	}
+
+	return flat
+}
+
+// FitInconsistent will fit the points based on the strategy of clustering
+// provided and cuts the dendrogram by the inconsistency coefficient of
+// the merges, see Dendrogram.CutInconsistent.
+// If the table is empty, the strategy is not known, depth is not positive
+// or the threshold is NaN this will return nil
+func FitInconsistent(points []distance.Distance, s strategy, threshold float64, depth int) []set.Set {
+	d := FitDendrogram(points, s)
+	if d == nil {
+		return nil
+	}
+
+	return d.CutInconsistent(threshold, depth)
+}
@@ -0,0 +1,96 @@
+package cluster_test
+
+import (
+	"math"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/util"
+	gc "gopkg.in/check.v1"
+)
+
+type inconsistencySuite struct{}
+
+var _ = gc.Suite(&inconsistencySuite{})
+
+// dendrogram returns the single linkage dendrogram of the
+// points 0, 1, 3, 7 with the merges at 1, 2 and 4
+func (is inconsistencySuite) dendrogram(c *gc.C) *cluster.Dendrogram {
+	distances := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	d := cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	c.Assert(d, gc.NotNil)
+	return d
+}
+
+func (is inconsistencySuite) round(stats []cluster.Inconsistency) []cluster.Inconsistency {
+	for i := range stats {
+		stats[i].Mean = util.Round(stats[i].Mean, 4)
+		stats[i].Deviation = util.Round(stats[i].Deviation, 4)
+		stats[i].Coefficient = util.Round(stats[i].Coefficient, 4)
+	}
+
+	return stats
+}
+
+func (is inconsistencySuite) TestInconsistency(c *gc.C) {
+	d := is.dendrogram(c)
+
+	stats := is.round(d.Inconsistency(1))
+	c.Assert(stats, gc.DeepEquals, []cluster.Inconsistency{
+		{Mean: 1, Count: 1},
+		{Mean: 2, Count: 1},
+		{Mean: 4, Count: 1},
+	})
+
+	stats = is.round(d.Inconsistency(2))
+	c.Assert(stats, gc.DeepEquals, []cluster.Inconsistency{
+		{Mean: 1, Count: 1},
+		{Mean: 1.5, Deviation: 0.7071, Count: 2, Coefficient: 0.7071},
+		{Mean: 3, Deviation: 1.4142, Count: 2, Coefficient: 0.7071},
+	})
+
+	stats = is.round(d.Inconsistency(3))
+	c.Assert(stats[2], gc.DeepEquals, cluster.Inconsistency{
+		Mean: 2.3333, Deviation: 1.5275, Count: 3, Coefficient: 1.0911,
+	})
+
+	c.Assert(d.Inconsistency(0), gc.IsNil)
+}
+
+func (is inconsistencySuite) TestCutInconsistent(c *gc.C) {
+	d := is.dendrogram(c)
+	c.Assert(d.CutInconsistent(0.5, 2), gc.DeepEquals, []set.Set{"x1,x2", "x3", "x4"})
+	c.Assert(d.CutInconsistent(0.8, 2), gc.DeepEquals, []set.Set{"x1,x2,x3,x4"})
+	c.Assert(d.CutInconsistent(0.8, 3), gc.DeepEquals, []set.Set{"x1,x2,x3", "x4"})
+
+	// the merges of one level are always consistent
+	c.Assert(d.CutInconsistent(0, 1), gc.DeepEquals, []set.Set{"x1,x2,x3,x4"})
+}
+
+func (is inconsistencySuite) TestCutInconsistentBelow(c *gc.C) {
+	// a consistent merge of an inconsistent one is not applied
+	d := cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3", "x4"},
+		Merges: []cluster.Merge{
+			{First: "x1", Second: "x2", Distance: 1, Size: 2},
+			{First: "x1,x2", Second: "x3", Distance: 5, Size: 3},
+			{First: "x1,x2,x3", Second: "x4", Distance: 5, Size: 4},
+		},
+	}
+	stats := d.Inconsistency(2)
+	c.Assert(stats[1].Coefficient > 0.5, gc.Equals, true)
+	c.Assert(stats[2].Coefficient, gc.Equals, 0.0)
+	c.Assert(d.CutInconsistent(0.5, 2), gc.DeepEquals, []set.Set{"x1,x2", "x3", "x4"})
+}
+
+func (is inconsistencySuite) TestFitInconsistent(c *gc.C) {
+	distances := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	clusters := cluster.FitInconsistent(distances, cluster.SingleLinkage, 0.5, 2)
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1,x2", "x3", "x4"})
+
+	c.Assert(cluster.FitInconsistent(nil, cluster.SingleLinkage, 0.5, 2), gc.IsNil)
+	c.Assert(cluster.FitInconsistent(distances, cluster.SingleLinkage, math.NaN(), 2), gc.IsNil)
+	c.Assert(cluster.FitInconsistent(distances, cluster.SingleLinkage, 0.5, 0), gc.IsNil)
+}
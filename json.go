@@ -0,0 +1,216 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// strategyNames holds the name of every strategy
+var strategyNames = map[strategy]string{
+	SingleLinkage:   "single",
+	CompleteLinkage: "complete",
+	AverageLinkage:  "average",
+	WardLinkage:     "ward",
+	CentroidLinkage: "centroid",
+	MedianLinkage:   "median",
+	WeightedLinkage: "weighted",
+}
+
+// MarshalText returns the name of the strategy, single, complete, average,
+// ward, centroid, median or weighted.
+// If the strategy is not known this will return an UnknownStrategyError
+func (s strategy) MarshalText() ([]byte, error) {
+	name, ok := strategyNames[s]
+	if !ok {
+		return nil, UnknownStrategyError{Strategy: uint8(s)}
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText sets the strategy from its name
+func (s *strategy) UnmarshalText(text []byte) error {
+	for linkage, name := range strategyNames {
+		if name == string(text) {
+			*s = linkage
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cluster: unknown linkage %q", string(text))
+}
+
+// Hierarchy is a fitted hierarchy that can be stored as json and read back,
+// the dendrogram of the merges with the strategy used to fit it and the
+// labels of the leaves. The merges, their distances and sizes are stored
+// as they are so the hierarchy read back gives the same clusters
+type Hierarchy struct {
+	// Linkage is the strategy used to fit the hierarchy
+	Linkage strategy `json:"linkage"`
+	// Labels are the labels of the leaves, in the order of the leaves.
+	// It can be empty
+	Labels []string `json:"labels,omitempty"`
+	Dendrogram
+}
+
+// FitHierarchy will fit the points the same way FitDendrogram does
+// and returns the hierarchy fitted with the strategy used.
+// If the table of distances is empty or the strategy
+// is not known this will return nil
+func FitHierarchy(points []distance.Distance, s strategy) *Hierarchy {
+	d := FitDendrogram(points, s)
+	if d == nil {
+		return nil
+	}
+
+	return &Hierarchy{Linkage: s, Dendrogram: *d}
+}
+
+// hierarchyJSON has the same fields as Hierarchy without its methods
+type hierarchyJSON Hierarchy
+
+// UnmarshalJSON reads the hierarchy from json and checks that
+// the linkage is set, the leaves follow the naming convention of the
+// set package, the labels match the leaves and every merge joins
+// two clusters of the leaves that are not merged yet.
+// If the hierarchy is not valid this will return one of InvalidSetError,
+// DuplicateSetError, InvalidMergeError or an error for the linkage
+// or the labels
+func (h *Hierarchy) UnmarshalJSON(data []byte) error {
+	var linkage struct {
+		Linkage *strategy `json:"linkage"`
+	}
+	if err := json.Unmarshal(data, &linkage); err != nil {
+		return err
+	}
+	if linkage.Linkage == nil {
+		return fmt.Errorf("cluster: missing linkage")
+	}
+
+	var read hierarchyJSON
+	if err := json.Unmarshal(data, &read); err != nil {
+		return err
+	}
+
+	if err := Hierarchy(read).check(); err != nil {
+		return err
+	}
+
+	*h = Hierarchy(read)
+	return nil
+}
+
+// check returns the first problem of the hierarchy
+func (h Hierarchy) check() error {
+	if len(h.Labels) != 0 && len(h.Labels) != len(h.Leaves) {
+		return fmt.Errorf("cluster: %d labels for %d leaves", len(h.Labels), len(h.Leaves))
+	}
+
+	// clusters holds the clusters not merged yet with the ids of their leaves
+	clusters := make(map[set.Set]set.IDs, len(h.Leaves))
+	points := make(map[string]bool, len(h.Leaves))
+	for i, leaf := range h.Leaves {
+		if !leaf.Valid() {
+			return InvalidSetError{Set: leaf}
+		}
+		for _, point := range leaf.Slice() {
+			if points[point] {
+				return DuplicateSetError{Set: leaf}
+			}
+			points[point] = true
+		}
+		clusters[leaf] = set.IDs{i}
+	}
+
+	for i, merge := range h.Merges {
+		first, ok := clusters[merge.First]
+		second, found := clusters[merge.Second]
+		if !ok || !found || merge.First == merge.Second {
+			return InvalidMergeError{Index: i, Merge: merge}
+		}
+
+		merged := append(set.IDs(nil), first...)
+		merged.Add(second)
+		cluster := merged.Set(h.Leaves)
+		if cluster.Len() != merge.Size {
+			return InvalidMergeError{Index: i, Merge: merge}
+		}
+
+		delete(clusters, merge.First)
+		delete(clusters, merge.Second)
+		clusters[cluster] = merged
+	}
+
+	return nil
+}
+
+// D3Node is a node of the nested form of the dendrogram read by d3.hierarchy,
+// every merge is a node with the two clusters merged as its children
+type D3Node struct {
+	// Name is the label of the leaf or the cluster of the merge,
+	// the points of the cluster joined by commas
+	Name string `json:"name"`
+	// Distance is the distance of the merge, 0 for the leaves
+	Distance float64 `json:"distance"`
+	// Size is the number of points of the node
+	Size int `json:"size"`
+	// Children are the clusters merged, empty for the leaves
+	Children []*D3Node `json:"children,omitempty"`
+}
+
+// D3 returns the root of the nested form of the dendrogram, the leaves are
+// named with the labels, in the order of the leaves, or after the leaves of
+// the dendrogram if there are no labels. A dendrogram that is not complete
+// has an unnamed root with every cluster as its children.
+// If there are no leaves this will return nil.
+// If the labels do not match the leaves this will return an error
+func (d Dendrogram) D3(labels []string) (*D3Node, error) {
+	n := len(d.Leaves)
+	if n == 0 {
+		return nil, nil
+	}
+
+	labels, err := d.labels(labels)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := d.steps()
+	children, roots := d.tree(steps)
+	nodes := make([]*D3Node, len(children), len(children))
+	ids := make([]set.IDs, len(children), len(children))
+	for leaf := 0; leaf < n; leaf++ {
+		ids[leaf] = set.IDs{leaf}
+		nodes[leaf] = &D3Node{
+			Name: labels[leaf],
+			Size: d.Leaves[leaf].Len(),
+		}
+	}
+	for i, s := range steps {
+		v := n + i
+		ids[v] = append(set.IDs(nil), ids[children[v][0]]...)
+		ids[v].Add(ids[children[v][1]])
+		a, b := nodes[children[v][0]], nodes[children[v][1]]
+		nodes[v] = &D3Node{
+			Name:     string(ids[v].Set(d.Leaves)),
+			Distance: s.distance,
+			Size:     a.Size + b.Size,
+			Children: []*D3Node{a, b},
+		}
+	}
+
+	if len(roots) == 1 {
+		return nodes[roots[0]], nil
+	}
+
+	root := &D3Node{}
+	for _, r := range roots {
+		root.Size += nodes[r].Size
+		root.Children = append(root.Children, nodes[r])
+	}
+
+	return root, nil
+}
@@ -0,0 +1,114 @@
+package cluster_test
+
+import (
+	"encoding/json"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type jsonSuite struct{}
+
+var _ = gc.Suite(&jsonSuite{})
+
+func (j jsonSuite) TestMarshalHierarchy(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	h := cluster.FitHierarchy(points, cluster.SingleLinkage)
+	h.Labels = []string{"a", "b", "c", "d"}
+
+	data, err := json.Marshal(h)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, `{"linkage":"single","labels":["a","b","c","d"],`+
+		`"leaves":["x1","x2","x3","x4"],"merges":[`+
+		`{"first":"x1","second":"x2","distance":1,"size":2},`+
+		`{"first":"x1,x2","second":"x3","distance":2,"size":3},`+
+		`{"first":"x1,x2,x3","second":"x4","distance":4,"size":4}]}`)
+
+	c.Assert(cluster.FitHierarchy(nil, cluster.SingleLinkage), gc.IsNil)
+}
+
+func (j jsonSuite) TestHierarchyRoundTrip(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0, 0.9, 3))
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		h := cluster.FitHierarchy(points, s)
+		data, err := json.Marshal(h)
+		c.Assert(err, gc.IsNil)
+
+		var read cluster.Hierarchy
+		c.Assert(json.Unmarshal(data, &read), gc.IsNil)
+		c.Assert(&read, gc.DeepEquals, h)
+		for k := 1; k <= len(points); k++ {
+			c.Assert(read.Cut(k), gc.DeepEquals, cluster.Fit(points, s, k))
+		}
+	}
+}
+
+func (j jsonSuite) TestUnmarshalHierarchyErrors(c *gc.C) {
+	tests := []struct {
+		data    string
+		message string
+	}{
+		{`{"linkage":"nearest"}`, `cluster: unknown linkage "nearest"`},
+		{`{"leaves":["x1","x2"]}`, `cluster: missing linkage`},
+		{`{"linkage":null,"leaves":["x1","x2"]}`, `cluster: missing linkage`},
+		{`{"linkage":"single","leaves":["x1","a"]}`, `cluster: invalid cluster name "a"`},
+		{`{"linkage":"single","leaves":["x1","x1"]}`, `cluster: duplicate cluster {x1}`},
+		{`{"linkage":"single","labels":["a"],"leaves":["x1","x2"]}`, `cluster: 1 labels for 2 leaves`},
+		{
+			`{"linkage":"single","leaves":["x1","x2","x3"],"merges":[{"first":"x1","second":"x4","size":2}]}`,
+			`cluster: invalid merge 0 of {x1} and {x4}`,
+		},
+		{
+			`{"linkage":"single","leaves":["x1","x2","x3"],"merges":[{"first":"x1","second":"x2","size":2},` +
+				`{"first":"x1","second":"x3","size":2}]}`,
+			`cluster: invalid merge 1 of {x1} and {x3}`,
+		},
+		{
+			`{"linkage":"single","leaves":["x1","x2","x3"],"merges":[{"first":"x1","second":"x2","size":3}]}`,
+			`cluster: invalid merge 0 of {x1} and {x2}`,
+		},
+	}
+
+	for _, test := range tests {
+		var h cluster.Hierarchy
+		err := json.Unmarshal([]byte(test.data), &h)
+		c.Assert(err, gc.NotNil, gc.Commentf(test.data))
+		c.Assert(err.Error(), gc.Equals, test.message)
+	}
+}
+
+func (j jsonSuite) TestD3(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3))
+	d := cluster.FitDendrogram(points, cluster.SingleLinkage)
+	root, err := d.D3([]string{"a", "b", "c"})
+	c.Assert(err, gc.IsNil)
+
+	data, err := json.Marshal(root)
+	c.Assert(err, gc.IsNil)
+	c.Assert(string(data), gc.Equals, `{"name":"x1,x2,x3","distance":2,"size":3,"children":[`+
+		`{"name":"x1,x2","distance":1,"size":2,"children":[`+
+		`{"name":"a","distance":0,"size":1},{"name":"b","distance":0,"size":1}]},`+
+		`{"name":"c","distance":0,"size":1}]}`)
+
+	// a dendrogram that is not complete has an unnamed root
+	d = &cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3"},
+		Merges: []cluster.Merge{{First: "x2", Second: "x3", Distance: 1, Size: 2}},
+	}
+	root, err = d.D3(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(root.Name, gc.Equals, "")
+	c.Assert(root.Size, gc.Equals, 3)
+	c.Assert(root.Children, gc.HasLen, 2)
+	c.Assert(root.Children[0].Name, gc.Equals, "x1")
+	c.Assert(root.Children[1].Name, gc.Equals, "x2,x3")
+
+	_, err = d.D3([]string{"a"})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 3 leaves")
+	root, err = cluster.Dendrogram{}.D3(nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(root, gc.IsNil)
+}
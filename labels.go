@@ -0,0 +1,54 @@
+package cluster
+
+import (
+	"sort"
+
+	"github.com/hoenirvili/cluster/distance"
+)
+
+// FitLabels will fit the points in k clusters the same way Fit does and
+// returns the label of every point, in the order of the rows of the table.
+// The tables built by distance.NewDistances follow the order of the points
+// so the labels are aligned with the points. See Dendrogram.Labels for
+// how the clusters are numbered.
+// If k is not in the range of the points this will return nil
+func FitLabels(points []distance.Distance, s strategy, k int) []int {
+	if k <= 0 || k > len(points) {
+		return nil
+	}
+
+	d := FitDendrogram(points, s)
+	if d == nil {
+		return nil
+	}
+
+	return d.Labels(k)
+}
+
+// Labels returns the label of every leaf when the dendrogram is cut
+// in k clusters, in the order of the leaves. The clusters are numbered
+// from 0 to k-1 in the order of their first leaf, the cluster of the first
+// leaf is always 0, the cluster of the next leaf not in it is 1 and so on.
+// If k is not in the range of the leaves this will return nil
+func (d Dendrogram) Labels(k int) []int {
+	n := len(d.Leaves)
+	if k <= 0 || k > n || n-k > len(d.Merges) {
+		return nil
+	}
+
+	cls := replay(n, d.steps(), func(n int, _ float64) bool {
+		return n == k
+	})
+	sort.Slice(cls, func(i, j int) bool {
+		return cls[i].Less(cls[j])
+	})
+
+	labels := make([]int, n, n)
+	for label, c := range cls {
+		for _, leaf := range c {
+			labels[leaf] = label
+		}
+	}
+
+	return labels
+}
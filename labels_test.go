@@ -0,0 +1,63 @@
+package cluster_test
+
+import (
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type labelsSuite struct{}
+
+var _ = gc.Suite(&labelsSuite{})
+
+func (l labelsSuite) TestFitLabels(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	labels := cluster.FitLabels(distances, cluster.SingleLinkage, 2)
+	c.Assert(labels, gc.DeepEquals, []int{0, 0, 0, 0, 1, 1, 1, 1})
+
+	labels = cluster.FitLabels(distances, cluster.SingleLinkage, 8)
+	c.Assert(labels, gc.DeepEquals, []int{0, 1, 2, 3, 4, 5, 6, 7})
+
+	labels = cluster.FitLabels(distances, cluster.SingleLinkage, 1)
+	c.Assert(labels, gc.DeepEquals, []int{0, 0, 0, 0, 0, 0, 0, 0})
+}
+
+func (l labelsSuite) TestLabelsMatchFit(c *gc.C) {
+	distances := nnchainSuite{}.randomDistances(5, 25)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		d := cluster.FitDendrogram(distances, s)
+		for k := 1; k <= len(distances); k++ {
+			labels := d.Labels(k)
+			clusters := cluster.Fit(distances, s, k)
+			c.Assert(labels, gc.HasLen, len(distances))
+
+			// every cluster of Fit has its own label
+			// following the order of the first leaf
+			for label, cls := range clusters {
+				for i, leaf := range d.Leaves {
+					c.Assert(labels[i] == label, gc.Equals, cls.In(leaf))
+				}
+			}
+		}
+	}
+}
+
+func (l labelsSuite) TestLabelsOrder(c *gc.C) {
+	// the second merge joins the clusters in the reverse order
+	d := cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3", "x4"},
+		Merges: []cluster.Merge{
+			{First: "x4", Second: "x2", Distance: 1, Size: 2},
+			{First: "x3", Second: "x1", Distance: 2, Size: 2},
+		},
+	}
+	c.Assert(d.Labels(2), gc.DeepEquals, []int{0, 1, 0, 1})
+}
+
+func (l labelsSuite) TestLabelsInvalid(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	c.Assert(cluster.FitLabels(distances, cluster.SingleLinkage, 0), gc.IsNil)
+	c.Assert(cluster.FitLabels(distances, cluster.SingleLinkage, 9), gc.IsNil)
+	c.Assert(cluster.FitLabels(nil, cluster.SingleLinkage, 1), gc.IsNil)
+	c.Assert(cluster.Dendrogram{}.Labels(1), gc.IsNil)
+}
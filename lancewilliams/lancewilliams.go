@@ -0,0 +1,147 @@
+// Package lancewilliams provides the Lance-Williams recurrence formula
+// used for updating the table of distances after two clusters are merged.
+// Every linkage criteria that can be written as
+//
+//	d(k, i∪j) = αi*d(k,i) + αj*d(k,j) + β*d(i,j) + γ*|d(k,i) - d(k,j)|
+//
+// can be expressed as a set of coefficients
+package lancewilliams
+
+import (
+	"math"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/util"
+)
+
+// Coefficients holds the coefficients of the Lance-Williams formula
+type Coefficients struct {
+	AlphaI float64
+	AlphaJ float64
+	Beta   float64
+	Gamma  float64
+}
+
+// Distance returns the distance from the cluster k to the cluster
+// formed by merging i and j based on the distances dki, dkj and dij
+func (c Coefficients) Distance(dki, dkj, dij float64) float64 {
+	return c.AlphaI*dki + c.AlphaJ*dkj + c.Beta*dij + c.Gamma*math.Abs(dki-dkj)
+}
+
+// Linkage defines a clustering criteria in terms of
+// the Lance-Williams coefficients
+type Linkage interface {
+	// Coefficients returns the coefficients used when the clusters
+	// i and j of size ni and nj are merged, relative to the cluster k
+	// of size nk
+	Coefficients(ni, nj, nk int) Coefficients
+	// Squared returns true if the formula must be applied
+	// on the squared distances instead of the distances
+	Squared() bool
+}
+
+// Reducible is implemented by the linkages that satisfy the reducibility
+// property, merging two clusters never brings the new cluster closer to a
+// third one than the closest of the two was
+//
+//	d(i∪j, k) >= min(d(i,k), d(j,k))
+//
+// These linkages can be computed with the nearest neighbor chain algorithm
+type Reducible interface {
+	// Reducible returns true if the linkage satisfies the reducibility property
+	Reducible() bool
+}
+
+// Flexible represents the flexible beta linkage
+// with the beta coefficient provided by the user.
+// Usually beta is chosen in the range [-1, 1), with -0.25 as
+// the most common choice
+type Flexible struct {
+	Beta float64
+}
+
+// NewFlexible creates a new flexible linkage with the given beta
+func NewFlexible(beta float64) *Flexible {
+	return &Flexible{Beta: beta}
+}
+
+var (
+	_ Linkage   = (*Flexible)(nil)
+	_ Reducible = (*Flexible)(nil)
+)
+
+// Coefficients returns the flexible beta coefficients
+// αi = αj = (1-β)/2 and γ = 0
+func (f Flexible) Coefficients(ni, nj, nk int) Coefficients {
+	alpha := (1 - f.Beta) / 2
+	return Coefficients{AlphaI: alpha, AlphaJ: alpha, Beta: f.Beta}
+}
+
+// Squared returns false, the flexible linkage is
+// applied on the distances
+func (f Flexible) Squared() bool { return false }
+
+// Reducible returns true only if beta is 0, the weighted linkage.
+// Only beta 0 is treated as reducible on purpose, a positive beta
+// breaks the reducibility property and any other beta is left to
+// the greedy algorithm so its merges never depend on the algorithm used
+func (f Flexible) Reducible() bool { return f.Beta == 0 }
+
+// Update merges the cluster j into the cluster i of the matrix and computes,
+// in a single pass, the distance from the merged cluster to every other
+// cluster using the coefficients of the linkage.
+// sizes holds the number of points of every cluster of the matrix, the
+// clusters with no points were merged already and are skipped.
+// If the linkage is squared the matrix must hold the squared distances.
+// After the update sizes[i] holds the size of the merged cluster and
+// sizes[j] is zero
+func Update(m *distance.Matrix, sizes []int, i, j int, linkage Linkage) {
+	n := m.Len()
+	if i == j || i < 0 || j < 0 || i >= n || j >= n {
+		return
+	}
+
+	ni, nj := sizes[i], sizes[j]
+	dij := m.At(i, j)
+	squared := linkage.Squared()
+	for k := 0; k < n; k++ {
+		if k == i || k == j || sizes[k] == 0 {
+			continue
+		}
+
+		c := linkage.Coefficients(ni, nj, sizes[k])
+		d := c.Distance(m.At(k, i), m.At(k, j), dij)
+		if squared {
+			// rounding errors can push the squared distance below zero
+			d = math.Max(d, 0)
+		}
+		m.Set(k, i, d)
+	}
+
+	sizes[i], sizes[j] = ni+nj, 0
+}
+
+// Square returns a copy of the matrix with every distance squared
+func Square(m *distance.Matrix) *distance.Matrix {
+	squares := m.Copy()
+	n := m.Len()
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := m.At(i, j)
+			squares.Set(i, j, d*d)
+		}
+	}
+
+	return squares
+}
+
+// Height returns the distance as it is reported by the linkage,
+// rounded to 4 decimals. If the linkage is squared this will
+// return the square root of the distance
+func Height(d float64, linkage Linkage) float64 {
+	if linkage.Squared() {
+		d = math.Sqrt(d)
+	}
+
+	return util.Round(d, 4)
+}
@@ -0,0 +1,111 @@
+package lancewilliams_test
+
+import (
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type lanceWilliamsSuite struct{}
+
+var _ = gc.Suite(&lanceWilliamsSuite{})
+
+// minimum is the single linkage
+type minimum struct{}
+
+func (m minimum) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5, Gamma: -0.5}
+}
+
+func (m minimum) Squared() bool { return false }
+
+func (l lanceWilliamsSuite) matrix() *distance.Matrix {
+	return distance.Condense([]distance.Distance{
+		{
+			Set: "x1",
+			Points: map[set.Set]float64{
+				"x2": 0.5,
+				"x3": 0.75,
+				"x4": 1,
+			},
+		},
+		{
+			Set: "x2",
+			Points: map[set.Set]float64{
+				"x3": 0.125,
+				"x4": 0.375,
+			},
+		},
+		{
+			Set: "x3",
+			Points: map[set.Set]float64{
+				"x4": 0.25,
+			},
+		},
+		{
+			Set:    "x4",
+			Points: nil,
+		},
+	})
+}
+
+func (l lanceWilliamsSuite) TestCoefficientsDistance(c *gc.C) {
+	coefficients := lancewilliams.Coefficients{
+		AlphaI: 1, AlphaJ: 2, Beta: 3, Gamma: 4,
+	}
+	c.Assert(coefficients.Distance(1, 3, 2), gc.Equals, 1.0+6.0+6.0+8.0)
+	c.Assert(lancewilliams.Coefficients{}.Distance(1, 3, 2), gc.Equals, 0.0)
+}
+
+func (l lanceWilliamsSuite) TestFlexible(c *gc.C) {
+	flexible := lancewilliams.NewFlexible(-0.25)
+	c.Assert(flexible, gc.NotNil)
+	c.Assert(flexible.Squared(), gc.Equals, false)
+	c.Assert(flexible.Coefficients(1, 2, 3), gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.625, AlphaJ: 0.625, Beta: -0.25,
+	})
+}
+
+func (l lanceWilliamsSuite) TestUpdate(c *gc.C) {
+	matrix := l.matrix()
+	sizes := []int{1, 1, 1, 1}
+
+	// invalid clusters leave the matrix untouched
+	lancewilliams.Update(matrix, sizes, 1, 1, minimum{})
+	lancewilliams.Update(matrix, sizes, 1, 4, minimum{})
+	c.Assert(matrix, gc.DeepEquals, l.matrix())
+	c.Assert(sizes, gc.DeepEquals, []int{1, 1, 1, 1})
+
+	lancewilliams.Update(matrix, sizes, 1, 2, minimum{})
+	c.Assert(sizes, gc.DeepEquals, []int{1, 2, 0, 1})
+	c.Assert(matrix.At(0, 1), gc.Equals, 0.5)
+	c.Assert(matrix.At(1, 3), gc.Equals, 0.25)
+	c.Assert(matrix.At(0, 3), gc.Equals, 1.0)
+
+	// the merged cluster is no longer updated
+	lancewilliams.Update(matrix, sizes, 3, 1, minimum{})
+	c.Assert(sizes, gc.DeepEquals, []int{1, 0, 0, 3})
+	c.Assert(matrix.At(0, 3), gc.Equals, 0.5)
+	c.Assert(matrix.At(2, 3), gc.Equals, 0.25)
+}
+
+func (l lanceWilliamsSuite) TestSquareAndHeight(c *gc.C) {
+	matrix := l.matrix()
+	squares := lancewilliams.Square(matrix)
+	c.Assert(squares.At(0, 1), gc.Equals, 0.25)
+	c.Assert(squares.At(2, 3), gc.Equals, 0.0625)
+	c.Assert(matrix.At(0, 1), gc.Equals, 0.5)
+
+	c.Assert(lancewilliams.Height(2.25, lancewilliams.NewFlexible(0)), gc.Equals, 2.25)
+	c.Assert(lancewilliams.Height(2.25, squared{}), gc.Equals, 1.5)
+}
+
+// squared is the median linkage
+type squared struct{}
+
+func (s squared) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5, Beta: -0.25}
+}
+
+func (s squared) Squared() bool { return true }
@@ -0,0 +1,107 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// FitMany will fit the points once and returns the clusters for every
+// number of clusters requested, the clusters of ks[i] are the same
+// clusters Fit returns for ks[i].
+// If k is not in the range of the points its clusters will be nil
+func FitMany(points []distance.Distance, s strategy, ks []int) [][]set.Set {
+	cuts := make([][]set.Set, len(ks), len(ks))
+	linkage := newLinkage(s)
+	if linkage == nil || len(points) == 0 {
+		return cuts
+	}
+
+	n := len(points)
+	at := make([]int, len(ks), len(ks))
+	for i, k := range ks {
+		at[i] = -1
+		if k > 0 && k <= n {
+			at[i] = n - k
+		}
+	}
+
+	cls := leaves(points)
+	steps, _ := hierarchy(distance.Condense(points), sizes(cls), linkage, nil, tracker{})
+	return snapshots(cls, steps, at)
+}
+
+// FitThresholds will fit the points once and returns the clusters for every
+// distance threshold, the clusters of thresholds[i] are the same clusters
+// FitThreshold returns for thresholds[i].
+// If a threshold is negative or NaN its clusters will be nil
+func FitThresholds(points []distance.Distance, s strategy, thresholds []float64) [][]set.Set {
+	cuts := make([][]set.Set, len(thresholds), len(thresholds))
+	linkage := newLinkage(s)
+	if linkage == nil || len(points) == 0 {
+		return cuts
+	}
+
+	cls := leaves(points)
+	steps, _ := hierarchy(distance.Condense(points), sizes(cls), linkage, nil, tracker{})
+	at := make([]int, len(thresholds), len(thresholds))
+	for i, threshold := range thresholds {
+		at[i] = -1
+		if threshold < 0 || math.IsNaN(threshold) {
+			continue
+		}
+
+		// stop before the first merge above the threshold
+		at[i] = len(steps)
+		for j, s := range steps {
+			if s.distance > threshold {
+				at[i] = j
+				break
+			}
+		}
+	}
+
+	return snapshots(cls, steps, at)
+}
+
+// snapshots replays the steps on the leaves once and returns the clusters
+// after at[i] steps were applied, for every i. If at[i] is negative or there
+// are not enough steps the clusters of at[i] will be nil
+func snapshots(leaves []set.Set, steps []step, at []int) [][]set.Set {
+	cuts := make([][]set.Set, len(at), len(at))
+	order := make([]int, 0, len(at))
+	for i, a := range at {
+		if a >= 0 && a <= len(steps) {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return at[order[i]] < at[order[j]]
+	})
+
+	cls := make([]set.IDs, len(leaves), len(leaves))
+	for i := range cls {
+		cls[i] = set.IDs{i}
+	}
+
+	applied := 0
+	for _, o := range order {
+		for ; applied < at[o]; applied++ {
+			s := steps[applied]
+			cls[s.first].Add(cls[s.second])
+			cls[s.second] = nil
+		}
+
+		cut := make([]set.Set, 0, len(leaves)-applied)
+		for _, c := range cls {
+			if c != nil {
+				cut = append(cut, c.Set(leaves))
+			}
+		}
+		cuts[o] = cut
+	}
+
+	return cuts
+}
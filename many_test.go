@@ -0,0 +1,63 @@
+package cluster_test
+
+import (
+	"math"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type manySuite struct{}
+
+var _ = gc.Suite(&manySuite{})
+
+func (m manySuite) TestFitMany(c *gc.C) {
+	tables := []func(*gc.C) []distance.Distance{
+		clusterSuite{}.oneDistances,
+		clusterSuite{}.twoDistances,
+	}
+
+	ks := []int{3, 1, 8, 0, 2, 9, 3, -1, 5, 4, 7, 6}
+	for _, table := range tables {
+		distances := table(c)
+		for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+			cuts := cluster.FitMany(distances, s, ks)
+			c.Assert(cuts, gc.HasLen, len(ks))
+			for i, k := range ks {
+				c.Assert(cuts[i], gc.DeepEquals, cluster.Fit(distances, s, k))
+			}
+		}
+	}
+}
+
+func (m manySuite) TestFitThresholds(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	thresholds := []float64{0.25, 0, 10, -1, 0.1, math.NaN(), 1.2, 0.5, 0.3}
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		cuts := cluster.FitThresholds(distances, s, thresholds)
+		c.Assert(cuts, gc.HasLen, len(thresholds))
+		for i, threshold := range thresholds {
+			c.Assert(cuts[i], gc.DeepEquals, cluster.FitThreshold(distances, s, threshold))
+		}
+	}
+}
+
+func (m manySuite) TestFitManyInvalid(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	c.Assert(cluster.FitMany(distances, cluster.WeightedLinkage+1, []int{1, 2}), gc.DeepEquals, [][]set.Set{nil, nil})
+	c.Assert(cluster.FitMany(nil, cluster.SingleLinkage, []int{1}), gc.DeepEquals, [][]set.Set{nil})
+	c.Assert(cluster.FitMany(distances, cluster.SingleLinkage, nil), gc.HasLen, 0)
+	c.Assert(cluster.FitThresholds(nil, cluster.SingleLinkage, []float64{1}), gc.DeepEquals, [][]set.Set{nil})
+}
+
+// benchmarks
+
+func (m manySuite) BenchmarkFitMany(c *gc.C) {
+	distances := nnchainSuite{}.randomDistances(1, 200)
+	ks := []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	for i := 0; i < c.N; i++ {
+		cluster.FitMany(distances, cluster.AverageLinkage, ks)
+	}
+}
@@ -0,0 +1,26 @@
+// Package medianlinkage provides basic semantics for choosing different
+// clusters that are best fitted for median clustering (WPGMC)
+package medianlinkage
+
+import "github.com/hoenirvili/cluster/lancewilliams"
+
+// MedianLinkage type that represents the median linkage
+// bottom up cluster semantics
+type MedianLinkage struct{}
+
+// NewMedianLinkage creates a new MedianLinkage pointer
+func NewMedianLinkage() *MedianLinkage {
+	return &MedianLinkage{}
+}
+
+var _ lancewilliams.Linkage = (*MedianLinkage)(nil)
+
+// Coefficients returns the median linkage coefficients
+// αi = αj = 1/2, β = -1/4 and γ = 0
+func (m MedianLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5, Beta: -0.25}
+}
+
+// Squared returns true, the median linkage formula is
+// applied on the squared euclidean distances
+func (m MedianLinkage) Squared() bool { return true }
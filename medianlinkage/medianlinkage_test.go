@@ -0,0 +1,30 @@
+package medianlinkage_test
+
+import (
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/medianlinkage"
+	gc "gopkg.in/check.v1"
+)
+
+type medianLinkageSuite struct{}
+
+var _ = gc.Suite(&medianLinkageSuite{})
+
+func (l medianLinkageSuite) TestNewMedianLinkage(c *gc.C) {
+	ml := medianlinkage.NewMedianLinkage()
+	c.Assert(ml, gc.NotNil)
+	c.Assert(ml.Squared(), gc.Equals, true)
+}
+
+func (l medianLinkageSuite) TestMedianLinkageCoefficients(c *gc.C) {
+	ml := medianlinkage.NewMedianLinkage()
+
+	// the size of the clusters does not matter
+	coefficients := ml.Coefficients(1, 3, 2)
+	c.Assert(coefficients, gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.5, AlphaJ: 0.5, Beta: -0.25, Gamma: 0,
+	})
+
+	// 16/2 + 9/2 - 1/4 = 49 / 4
+	c.Assert(coefficients.Distance(16, 9, 1), gc.Equals, 49.0/4.0)
+}
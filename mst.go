@@ -0,0 +1,279 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hoenirvili/cluster/dimension"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/util"
+)
+
+// edge is an edge of the minimum spanning tree
+// between the points a and b
+type edge struct {
+	a, b   int
+	length float64
+}
+
+// spanning returns the n-1 edges of the minimum spanning tree of n points
+// using Prim's algorithm on the dense metric. The distance between two
+// points is computed on demand, this needs O(n^2) time and O(n) memory.
+// The edges are returned in the order they were added to the tree.
+// If the fit is cancelled this will return the error of the context
+func spanning(n int, length func(i, j int) float64, t tracker) ([]edge, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	closest := make([]float64, n, n)
+	parent := make([]int, n, n)
+	done := make([]bool, n, n)
+	for i := range closest {
+		closest[i] = math.Inf(1)
+	}
+
+	edges := make([]edge, 0, n-1)
+	current := 0
+	done[current] = true
+	for len(edges) < n-1 {
+		next := -1
+		for k := 0; k < n; k++ {
+			if done[k] {
+				continue
+			}
+			if d := length(current, k); d < closest[k] {
+				closest[k], parent[k] = d, current
+			}
+			if next == -1 || closest[k] < closest[next] {
+				next = k
+			}
+		}
+
+		edges = append(edges, edge{a: parent[next], b: next, length: closest[next]})
+		done[next] = true
+		current = next
+		if err := t.err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return edges, nil
+}
+
+// kruskal turns the edges of the minimum spanning tree into the single
+// linkage merges, size holds the number of points of every cluster and
+// length returns the distance between two points used to build the tree.
+// The edges are applied from the shortest to the longest, edges of the
+// same length are applied from the lowest pair of clusters they join,
+// the same merges the greedy algorithm makes with the LowestIndex policy.
+// The merged cluster takes the place of the cluster with the lowest index.
+// If two clusters are at the length of the edges without an edge
+// between them the order of the merges is ambiguous and this
+// will return false, unless length is nil and this is not checked
+func kruskal(size []int, edges []edge, length func(i, j int) float64, t tracker) ([]step, bool, error) {
+	for k, e := range edges {
+		if e.b < e.a {
+			edges[k].a, edges[k].b = e.b, e.a
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].length != edges[j].length {
+			return edges[i].length < edges[j].length
+		}
+		if edges[i].a != edges[j].a {
+			return edges[i].a < edges[j].a
+		}
+		return edges[i].b < edges[j].b
+	})
+
+	// root holds for every point the lowest point index of its
+	// cluster, only the root of every cluster holds its size.
+	// next links the points of every cluster starting from its root
+	// and last holds the last point of every cluster
+	n := len(size)
+	root := make([]int, n, n)
+	next := make([]int, n, n)
+	last := make([]int, n, n)
+	size = append([]int(nil), size...)
+	for i := range root {
+		root[i], next[i], last[i] = i, -1, i
+	}
+	find := func(i int) int {
+		for root[i] != i {
+			root[i] = root[root[i]]
+			i = root[i]
+		}
+		return i
+	}
+
+	steps := make([]step, 0, len(edges))
+	for start, end := 0, 0; start < len(edges); start = end {
+		for end = start + 1; end < len(edges) && edges[end].length == edges[start].length; end++ {
+		}
+		group := edges[start:end]
+		if len(group) > 1 && length != nil && !separated(group, find, next, length) {
+			return nil, false, nil
+		}
+
+		for len(group) > 0 {
+			// apply first the edge between the lowest pair of clusters
+			best, i, j := -1, 0, 0
+			for k, e := range group {
+				a, b := find(e.a), find(e.b)
+				if b < a {
+					a, b = b, a
+				}
+				if best == -1 || a < i || (a == i && b < j) {
+					best, i, j = k, a, b
+				}
+			}
+			steps = append(steps, step{
+				first:    i,
+				second:   j,
+				distance: group[best].length,
+				size:     size[i] + size[j],
+			})
+			group[best] = group[len(group)-1]
+			group = group[:len(group)-1]
+
+			root[j] = i
+			size[i] += size[j]
+			next[last[i]], last[i] = j, last[j]
+			if err := t.err(); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	return steps, true, nil
+}
+
+// separated returns true if the clusters joined by the edges of the group,
+// all of the same length, are at that length only where an edge joins them.
+// Two clusters at the length of the edges without an edge between them
+// could be merged first by the greedy algorithm.
+// Only the clusters joined by two edges or more need to be checked, the
+// minimum spanning tree holds every other pair of points at that length
+func separated(group []edge, find func(int) int, next []int, length func(i, j int) float64) bool {
+	// block joins the clusters of the edges of the group
+	block := make(map[int]int, 2*len(group))
+	var top func(int) int
+	top = func(r int) int {
+		if b, ok := block[r]; ok && b != r {
+			block[r] = top(b)
+			return block[r]
+		}
+		block[r] = r
+		return r
+	}
+
+	joined := make(map[[2]int]bool, len(group))
+	for _, e := range group {
+		a, b := find(e.a), find(e.b)
+		joined[[2]int{a, b}], joined[[2]int{b, a}] = true, true
+		block[top(b)] = top(a)
+	}
+
+	clusters := make(map[int][]int, len(block))
+	for r := range block {
+		clusters[top(r)] = append(clusters[top(r)], r)
+	}
+
+	h := group[0].length
+	for _, roots := range clusters {
+		if len(roots) < 3 {
+			continue
+		}
+		for x, a := range roots {
+			for _, b := range roots[x+1:] {
+				if joined[[2]int{a, b}] {
+					continue
+				}
+				for i := a; i != -1; i = next[i] {
+					for j := b; j != -1; j = next[j] {
+						if length(i, j) <= h {
+							return false
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// mst computes the single linkage merges of the matrix of distances
+// from its minimum spanning tree. If the order of the merges of the
+// same distance is ambiguous this will return false,
+// the greedy algorithm should be used instead
+func mst(m *distance.Matrix, size []int, t tracker) ([]step, bool, error) {
+	length := func(i, j int) float64 {
+		return util.Round(m.At(i, j), 4)
+	}
+	edges, err := spanning(m.Len(), length, t)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return kruskal(size, edges, length, t)
+}
+
+// FitSingleLinkage will fit the points in k clusters based on the single
+// linkage strategy, the points are named x1, x2, x3, ... same as
+// distance.NewDistances names them. The merges are computed from the
+// minimum spanning tree of the points, this needs O(n^2) time and O(n)
+// memory and the table of distances is never built.
+// When two merges happen at the same distance the one with the lowest
+// pair of clusters is made first, so the clusters can differ from the
+// ones Fit returns only when two clusters tie without an edge of the
+// tree between them.
+// If k is not in the range of the points this will return nil
+func FitSingleLinkage(points []dimension.Distancer, k int) []set.Set {
+	if k <= 0 || k > len(points) {
+		return nil
+	}
+
+	return cut(names(len(points)), spanningSteps(points), func(n int, _ float64) bool {
+		return n == k
+	})
+}
+
+// FitSingleLinkageDendrogram will fit the points based on the single
+// linkage strategy until one cluster remains and returns every merge made,
+// in the same way FitSingleLinkage does.
+// If there are no points this will return nil
+func FitSingleLinkageDendrogram(points []dimension.Distancer) *Dendrogram {
+	if len(points) == 0 {
+		return nil
+	}
+
+	cls := names(len(points))
+	return &Dendrogram{
+		Leaves: cls,
+		Merges: merges(cls, spanningSteps(points)),
+	}
+}
+
+// spanningSteps returns the single linkage merges
+// computed from the minimum spanning tree of the points
+func spanningSteps(points []dimension.Distancer) []step {
+	n := len(points)
+	length := func(i, j int) float64 {
+		return util.Round(points[i].Distance(points[j]), 4)
+	}
+	edges, _ := spanning(n, length, tracker{})
+
+	size := make([]int, n, n)
+	for i := range size {
+		size[i] = 1
+	}
+	steps, ok, _ := kruskal(size, edges, length, tracker{})
+	if !ok {
+		steps, _, _ = kruskal(size, edges, nil, tracker{})
+	}
+
+	return steps
+}
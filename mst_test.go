@@ -0,0 +1,83 @@
+package cluster_test
+
+import (
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/two"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/singlelinkage"
+	gc "gopkg.in/check.v1"
+)
+
+type mstSuite struct{}
+
+var _ = gc.Suite(&mstSuite{})
+
+func (m mstSuite) TestFitSingleLinkageMatchesFit(c *gc.C) {
+	for seed := int64(1); seed <= 5; seed++ {
+		points := nnchainSuite{}.randomPoints(seed, 30)
+		distances := distance.NewDistances(points)
+		for k := len(points); k > 0; k-- {
+			expected := cluster.Fit(distances, cluster.SingleLinkage, k)
+			clusters := cluster.FitSingleLinkage(points, k)
+			c.Assert(clusters, gc.DeepEquals, expected)
+		}
+	}
+}
+
+func (m mstSuite) TestFitSingleLinkageDendrogram(c *gc.C) {
+	points := nnchainSuite{}.randomPoints(7, 40)
+	expected := cluster.FitDendrogram(distance.NewDistances(points), cluster.SingleLinkage)
+	dendrogram := cluster.FitSingleLinkageDendrogram(points)
+	c.Assert(dendrogram, gc.DeepEquals, expected)
+}
+
+func (m mstSuite) TestFitSingleLinkageTies(c *gc.C) {
+	// the one dimension points have a lot of ties
+	distances := clusterSuite{}.oneDistances(c)
+	linkage := singlelinkage.NewSingleLinkage()
+	for k := len(distances); k > 0; k-- {
+		expected := cluster.FitWith(distances, greedyLinkage{linkage}, k)
+		clusters := cluster.FitWith(distances, linkage, k)
+		c.Assert(clusters, gc.DeepEquals, expected)
+	}
+}
+
+func (m mstSuite) TestMSTRandom(c *gc.C) {
+	// a few hundred points have edges of the same length
+	// after they are rounded, kruskal must handle them
+	distances := nnchainSuite{}.randomDistances(1, 500)
+	d, ok := cluster.MSTDendrogram(distances)
+	c.Assert(ok, gc.Equals, true)
+	c.Assert(d, gc.DeepEquals, cluster.GreedyDendrogram(distances, singlelinkage.NewSingleLinkage()))
+}
+
+func (m mstSuite) TestMSTGrid(c *gc.C) {
+	// every point of the grid is at the same distance from its neighbors
+	points := two.NewDistances(
+		[]float64{0, 1, 2, 0, 1, 2, 0, 1, 2},
+		[]float64{2, 2, 2, 1, 1, 1, 0, 0, 0},
+	)
+	distances := distance.NewDistances(points)
+	expected := cluster.GreedyDendrogram(distances, singlelinkage.NewSingleLinkage())
+	if d, ok := cluster.MSTDendrogram(distances); ok {
+		c.Assert(d, gc.DeepEquals, expected)
+	}
+	c.Assert(cluster.FitDendrogram(distances, cluster.SingleLinkage), gc.DeepEquals, expected)
+}
+
+func (m mstSuite) TestFitSingleLinkageInvalid(c *gc.C) {
+	points := nnchainSuite{}.randomPoints(1, 5)
+	c.Assert(cluster.FitSingleLinkage(points, 0), gc.IsNil)
+	c.Assert(cluster.FitSingleLinkage(points, 6), gc.IsNil)
+	c.Assert(cluster.FitSingleLinkage(nil, 1), gc.IsNil)
+	c.Assert(cluster.FitSingleLinkageDendrogram(nil), gc.IsNil)
+}
+
+// benchmarks
+
+func (m mstSuite) BenchmarkFitSingleLinkage(c *gc.C) {
+	points := nnchainSuite{}.randomPoints(1, 1000)
+	for i := 0; i < c.N; i++ {
+		cluster.FitSingleLinkage(points, 1)
+	}
+}
@@ -0,0 +1,438 @@
+package cluster
+
+import (
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/util"
+)
+
+// newickDelimiters holds the characters that end
+// an unquoted label in the Newick format
+const newickDelimiters = "()[]':;, \t\r\n"
+
+// WriteNewick writes the dendrogram in the Newick format, the leaves
+// are named with the labels, in the order of the leaves, or after the
+// leaves of the dendrogram if there are no labels. The length of every
+// branch is the difference between the heights of the two merges it joins,
+// rounded to 4 decimals. The leaves have the height 0.
+// A dendrogram that is not complete is written as one tree for every cluster.
+// If the labels do not match the leaves this will return an error
+func (d Dendrogram) WriteNewick(w io.Writer, labels []string) error {
+	n := len(d.Leaves)
+	if n == 0 {
+		return nil
+	}
+
+	labels, err := d.labels(labels)
+	if err != nil {
+		return err
+	}
+
+	steps := d.steps()
+	children, roots := d.tree(steps)
+	height := func(node int) float64 {
+		if node < n {
+			return 0
+		}
+		return steps[node-n].distance
+	}
+
+	var text strings.Builder
+	var write func(node int)
+	write = func(node int) {
+		if node < n {
+			text.WriteString(newickQuote(labels[node]))
+			return
+		}
+
+		text.WriteByte('(')
+		for i, child := range children[node] {
+			if i > 0 {
+				text.WriteByte(',')
+			}
+			write(child)
+			text.WriteByte(':')
+			text.WriteString(formatHeight(height(node) - height(child)))
+		}
+		text.WriteByte(')')
+	}
+	for _, root := range roots {
+		write(root)
+		text.WriteString(";\n")
+	}
+
+	_, err = io.WriteString(w, text.String())
+	return err
+}
+
+// newickQuote returns the label as it is written in the Newick format,
+// between single quotes if it holds special characters
+func newickQuote(label string) string {
+	if label != "" && !strings.ContainsAny(label, newickDelimiters+"_") {
+		return label
+	}
+
+	return "'" + strings.ReplaceAll(label, "'", "''") + "'"
+}
+
+// ParseNewick reads one or more trees in the Newick format into a dendrogram
+// and returns the names of the leaves read, in the order of the leaves.
+// The height of every node is the sum of the lengths of the branches down
+// to its first leaf, rounded to 4 decimals, missing lengths are 0.
+// The merges are made from the lowest to the highest height, the merges at
+// the same height from the lowest pair of clusters the same way Fit breaks
+// the ties, and a node with more than two children is split in several
+// merges at the same height.
+// If every name follows the naming convention of the set package the leaves
+// are named and sorted by their names, otherwise the leaves keep the order
+// they are written in and are named x1, x2, x3, ... same as
+// distance.NewDistances names them.
+// Comments and the labels of the inner nodes are ignored.
+// If the text is not a valid tree this will return a NewickError
+func ParseNewick(r io.Reader) (*Dendrogram, []string, error) {
+	text, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &newickParser{text: string(text)}
+	trees := 0
+	for p.skip(); p.pos < len(p.text); p.skip() {
+		if _, err := p.subtree(); err != nil {
+			return nil, nil, err
+		}
+		p.skip()
+		if p.pos < len(p.text) && p.text[p.pos] == ':' {
+			p.pos++
+			if _, err := p.length(); err != nil {
+				return nil, nil, err
+			}
+			p.skip()
+		}
+		if p.pos >= len(p.text) || p.text[p.pos] != ';' {
+			return nil, nil, p.error("expected ;")
+		}
+		p.pos++
+		trees++
+	}
+	if trees == 0 {
+		return nil, nil, p.error("empty tree")
+	}
+
+	return p.dendrogram()
+}
+
+// newickNode is a node of a tree read in the Newick format
+type newickNode struct {
+	name     string
+	children []int
+	length   float64
+}
+
+// newickParser reads the trees of the text in the Newick format
+type newickParser struct {
+	text  string
+	pos   int
+	nodes []newickNode
+}
+
+// error returns the error found at the current position of the text
+func (p *newickParser) error(reason string) error {
+	return NewickError{Offset: p.pos, Reason: reason}
+}
+
+// skip moves past the white space and the comments
+func (p *newickParser) skip() {
+	for p.pos < len(p.text) {
+		switch p.text[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '[':
+			end := strings.IndexByte(p.text[p.pos:], ']')
+			if end == -1 {
+				p.pos = len(p.text)
+				return
+			}
+			p.pos += end + 1
+		default:
+			return
+		}
+	}
+}
+
+// subtree reads a leaf or a node with its children
+// and returns the index of the node read
+func (p *newickParser) subtree() (int, error) {
+	p.skip()
+	if p.pos >= len(p.text) {
+		return 0, p.error("unexpected end of the tree")
+	}
+
+	var children []int
+	if p.text[p.pos] == '(' {
+		for {
+			p.pos++
+			child, err := p.subtree()
+			if err != nil {
+				return 0, err
+			}
+			p.skip()
+			if p.pos < len(p.text) && p.text[p.pos] == ':' {
+				p.pos++
+				if p.nodes[child].length, err = p.length(); err != nil {
+					return 0, err
+				}
+				p.skip()
+			}
+			children = append(children, child)
+
+			if p.pos >= len(p.text) {
+				return 0, p.error("expected )")
+			}
+			if p.text[p.pos] == ')' {
+				p.pos++
+				break
+			}
+			if p.text[p.pos] != ',' {
+				return 0, p.error("expected , or )")
+			}
+		}
+		p.skip()
+	}
+
+	name, err := p.label()
+	if err != nil {
+		return 0, err
+	}
+	if children == nil && name == "" {
+		return 0, p.error("leaf without a name")
+	}
+	if children != nil {
+		name = ""
+	}
+
+	p.nodes = append(p.nodes, newickNode{name: name, children: children})
+	return len(p.nodes) - 1, nil
+}
+
+// label reads a quoted or an unquoted label, the underscores
+// of the unquoted labels are read as blanks
+func (p *newickParser) label() (string, error) {
+	if p.pos < len(p.text) && p.text[p.pos] == '\'' {
+		var label strings.Builder
+		for p.pos++; p.pos < len(p.text); p.pos++ {
+			if p.text[p.pos] != '\'' {
+				label.WriteByte(p.text[p.pos])
+				continue
+			}
+			if p.pos+1 < len(p.text) && p.text[p.pos+1] == '\'' {
+				label.WriteByte('\'')
+				p.pos++
+				continue
+			}
+			p.pos++
+			return label.String(), nil
+		}
+		return "", p.error("unterminated quoted label")
+	}
+
+	start := p.pos
+	for p.pos < len(p.text) && strings.IndexByte(newickDelimiters, p.text[p.pos]) == -1 {
+		p.pos++
+	}
+
+	return strings.ReplaceAll(p.text[start:p.pos], "_", " "), nil
+}
+
+// length reads the length of a branch
+func (p *newickParser) length() (float64, error) {
+	p.skip()
+	start := p.pos
+	for p.pos < len(p.text) && strings.IndexByte("+-.0123456789eE", p.text[p.pos]) != -1 {
+		p.pos++
+	}
+
+	length, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+	if err != nil || math.IsNaN(length) || math.IsInf(length, 0) {
+		p.pos = start
+		return 0, p.error("invalid branch length")
+	}
+
+	return length, nil
+}
+
+// dendrogram returns the dendrogram of the trees read
+// and the names of its leaves
+func (p *newickParser) dendrogram() (*Dendrogram, []string, error) {
+	// leaf holds the index of the leaf of every node, -1 for the inner nodes
+	leaf := make([]int, len(p.nodes), len(p.nodes))
+	names := make([]string, 0)
+	valid := true
+	for v, nd := range p.nodes {
+		leaf[v] = -1
+		if nd.children == nil {
+			leaf[v] = len(names)
+			names = append(names, nd.name)
+			valid = valid && set.Set(nd.name).Valid()
+		}
+	}
+
+	leaves := make([]set.Set, len(names), len(names))
+	for i, name := range names {
+		leaves[i] = distance.Name(i)
+		if valid {
+			leaves[i] = set.Set(name)
+		}
+	}
+	if valid {
+		sort.SliceStable(leaves, func(i, j int) bool {
+			return leaves[i].Priority(leaves[j])
+		})
+	}
+
+	// index holds the leaf of every name
+	index := make(map[string]int, len(names))
+	labels := make([]string, len(names), len(names))
+	for i, name := range names {
+		if _, ok := index[name]; ok {
+			return nil, nil, p.error("duplicate leaf " + name)
+		}
+		index[name] = i
+	}
+	if valid {
+		for i, leaf := range leaves {
+			index[string(leaf)] = i
+		}
+	}
+	for name, i := range index {
+		labels[i] = name
+	}
+
+	// every node is a cluster with its height, the lowest index of its
+	// leaves and its size. The children of every node come before it
+	// so a single pass computes them from the leaves up
+	height := make([]float64, len(p.nodes), len(p.nodes))
+	lowest := make([]int, len(p.nodes), len(p.nodes))
+	size := make([]int, len(p.nodes), len(p.nodes))
+	for v, nd := range p.nodes {
+		if leaf[v] != -1 {
+			lowest[v], size[v] = index[names[leaf[v]]], 1
+			continue
+		}
+		first := nd.children[0]
+		height[v] = util.Round(height[first]+p.nodes[first].length, 4)
+		lowest[v] = len(leaves)
+		for _, child := range nd.children {
+			if lowest[child] < lowest[v] {
+				lowest[v] = lowest[child]
+			}
+			size[v] += size[child]
+		}
+	}
+
+	// every inner node of k children makes k-1 merges at its height, joining
+	// every child with the next one. A merge is made after the merges of the
+	// two children it joins, so its order is the highest height up to the merge
+	type join struct {
+		node, a, b int
+		height     float64
+		order      float64
+	}
+	joins := make([]join, 0, len(leaves))
+	order := make([]float64, len(p.nodes), len(p.nodes))
+	pending := make([]int, len(p.nodes), len(p.nodes))
+	for v, nd := range p.nodes {
+		if leaf[v] != -1 {
+			continue
+		}
+		order[v] = height[v]
+		pending[v] = len(nd.children) - 1
+		for i, b := range nd.children[1:] {
+			a := nd.children[i]
+			order[v] = math.Max(order[v], math.Max(order[a], order[b]))
+			joins = append(joins, join{
+				node:   v,
+				a:      a,
+				b:      b,
+				height: height[v],
+				order:  order[v],
+			})
+		}
+	}
+	sort.SliceStable(joins, func(i, j int) bool {
+		return joins[i].order < joins[j].order
+	})
+
+	// root holds for every leaf the lowest leaf of its cluster,
+	// only the root of every cluster holds its size
+	root := make([]int, len(leaves), len(leaves))
+	count := make([]int, len(leaves), len(leaves))
+	for i := range root {
+		root[i], count[i] = i, 1
+	}
+	find := func(i int) int {
+		for root[i] != i {
+			root[i] = root[root[i]]
+			i = root[i]
+		}
+		return i
+	}
+	complete := func(v int) bool {
+		return leaf[v] != -1 || pending[v] == 0
+	}
+
+	steps := make([]step, 0, len(joins))
+	done := make([]bool, len(joins), len(joins))
+	for start, end := 0, 0; start < len(joins); start = end {
+		for end = start + 1; end < len(joins) && joins[end].order == joins[start].order; end++ {
+		}
+
+		// the merges of the same order are made from the lowest pair of
+		// clusters, the same way the greedy algorithm breaks the ties,
+		// once the merges of the children they join are made
+		for range joins[start:end] {
+			best, first, second := -1, 0, 0
+			for x := start; x < end; x++ {
+				j := joins[x]
+				if done[x] || !complete(j.a) || !complete(j.b) {
+					continue
+				}
+				a, b := find(lowest[j.a]), find(lowest[j.b])
+				if b < a {
+					a, b = b, a
+				}
+				if best == -1 || a < first || (a == first && b < second) {
+					best, first, second = x, a, b
+				}
+			}
+
+			j := joins[best]
+			done[best] = true
+			pending[j.node]--
+			root[second] = first
+			count[first] += count[second]
+
+			s := step{
+				first:    first,
+				second:   second,
+				distance: j.height,
+				size:     count[first],
+			}
+			if last := len(steps) - 1; last >= 0 {
+				s.inversion = j.height < steps[last].distance
+			}
+			steps = append(steps, s)
+		}
+	}
+
+	return &Dendrogram{
+		Leaves: leaves,
+		Merges: merges(leaves, steps),
+	}, labels, nil
+}
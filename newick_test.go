@@ -0,0 +1,137 @@
+package cluster_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/util"
+	gc "gopkg.in/check.v1"
+)
+
+type newickSuite struct{}
+
+var _ = gc.Suite(&newickSuite{})
+
+func (n newickSuite) TestWriteNewick(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	d := cluster.FitDendrogram(points, cluster.SingleLinkage)
+
+	var buf bytes.Buffer
+	c.Assert(d.WriteNewick(&buf, nil), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "(((x1:1,x2:1):1,x3:2):2,x4:4);\n")
+
+	buf.Reset()
+	c.Assert(d.WriteNewick(&buf, []string{"a", "b c", "it's", "d_e"}), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "(((a:1,'b c':1):1,'it''s':2):2,'d_e':4);\n")
+
+	err := d.WriteNewick(&buf, []string{"a"})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 4 leaves")
+}
+
+func (n newickSuite) TestNewickRoundTrip(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0, 0.9, 3))
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		d := cluster.FitDendrogram(points, s)
+		var buf bytes.Buffer
+		c.Assert(d.WriteNewick(&buf, nil), gc.IsNil)
+
+		parsed, labels, err := cluster.ParseNewick(&buf)
+		c.Assert(err, gc.IsNil)
+		c.Assert(parsed.Leaves, gc.DeepEquals, d.Leaves)
+		c.Assert(labels, gc.DeepEquals, []string{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8", "x9", "x10"})
+		c.Assert(parsed.Merges, gc.HasLen, len(d.Merges))
+		for i, merge := range parsed.Merges {
+			c.Assert(merge.First, gc.Equals, d.Merges[i].First)
+			c.Assert(merge.Second, gc.Equals, d.Merges[i].Second)
+			c.Assert(merge.Size, gc.Equals, d.Merges[i].Size)
+			c.Assert(merge.Inversion, gc.Equals, d.Merges[i].Inversion)
+			c.Assert(merge.Distance, gc.Equals, util.Round(d.Merges[i].Distance, 4))
+		}
+		for k := 1; k <= len(points); k++ {
+			c.Assert(parsed.Cut(k), gc.DeepEquals, d.Cut(k))
+		}
+	}
+}
+
+func (n newickSuite) TestNewickRoundTripTies(c *gc.C) {
+	// the one dimension points have a lot of merges at the same height
+	tables := [][]distance.Distance{
+		clusterSuite{}.oneDistances(c),
+		distance.NewDistances(one.NewDistances(1, 0, 2, 1, 3, 4, 5)),
+	}
+	for _, points := range tables {
+		for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+			d := cluster.FitDendrogram(points, s)
+			var buf bytes.Buffer
+			c.Assert(d.WriteNewick(&buf, nil), gc.IsNil)
+
+			parsed, _, err := cluster.ParseNewick(&buf)
+			c.Assert(err, gc.IsNil)
+			for k := 1; k <= len(points); k++ {
+				c.Assert(parsed.Cut(k), gc.DeepEquals, d.Cut(k), gc.Commentf("strategy %d k %d", s, k))
+			}
+		}
+	}
+}
+
+func (n newickSuite) TestParseNewick(c *gc.C) {
+	d, labels, err := cluster.ParseNewick(strings.NewReader(
+		"((x3:1,x1:1)inner:2,[comment] (x2:0.5, x4:0.5):2.5):0;"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(labels, gc.DeepEquals, []string{"x1", "x2", "x3", "x4"})
+	c.Assert(d, gc.DeepEquals, &cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3", "x4"},
+		Merges: []cluster.Merge{
+			{First: "x2", Second: "x4", Distance: 0.5, Size: 2},
+			{First: "x1", Second: "x3", Distance: 1, Size: 2},
+			{First: "x1,x3", Second: "x2,x4", Distance: 3, Size: 4},
+		},
+	})
+}
+
+func (n newickSuite) TestParseNewickNames(c *gc.C) {
+	// the leaves that do not follow the naming convention
+	// of the set package are named in the order they are read
+	d, labels, err := cluster.ParseNewick(strings.NewReader("((a:1,b_c:1,'d''s':1):1,e:2);"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(labels, gc.DeepEquals, []string{"a", "b c", "d's", "e"})
+	c.Assert(d, gc.DeepEquals, &cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3", "x4"},
+		Merges: []cluster.Merge{
+			{First: "x1", Second: "x2", Distance: 1, Size: 2},
+			{First: "x1,x2", Second: "x3", Distance: 1, Size: 3},
+			{First: "x1,x2,x3", Second: "x4", Distance: 2, Size: 4},
+		},
+	})
+
+	var buf bytes.Buffer
+	c.Assert(d.WriteNewick(&buf, labels), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "(((a:1,'b c':1):0,'d''s':1):1,e:2);\n")
+}
+
+func (n newickSuite) TestParseNewickErrors(c *gc.C) {
+	tests := []struct {
+		text    string
+		message string
+	}{
+		{"", "cluster: invalid newick tree at offset 0, empty tree"},
+		{"((a,b),c)", "cluster: invalid newick tree at offset 9, expected ;"},
+		{"((a:x,b),c);", "cluster: invalid newick tree at offset 4, invalid branch length"},
+		{"((a,b),(c,a));", "cluster: invalid newick tree at offset 14, duplicate leaf a"},
+		{"((a,b),c;", "cluster: invalid newick tree at offset 8, expected , or \\)"},
+		{"((a,),c);", "cluster: invalid newick tree at offset 4, leaf without a name"},
+		{"(a,'b);", "cluster: invalid newick tree at offset 7, unterminated quoted label"},
+	}
+
+	for _, test := range tests {
+		d, labels, err := cluster.ParseNewick(strings.NewReader(test.text))
+		c.Assert(err, gc.ErrorMatches, test.message, gc.Commentf(test.text))
+		c.Assert(err, gc.FitsTypeOf, cluster.NewickError{})
+		c.Assert(d, gc.IsNil)
+		c.Assert(labels, gc.IsNil)
+	}
+}
@@ -0,0 +1,169 @@
+package cluster
+
+import (
+	"sort"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/lancewilliams"
+)
+
+// nnchain computes every merge of the matrix using the nearest neighbor
+// chain algorithm, following the chain of nearest neighbors until two
+// clusters are each other's nearest neighbor. This needs O(n^2) time
+// compared with the greedy algorithm but it is only valid for
+// the linkages that satisfy the reducibility property.
+// When the nearest neighbors of a cluster tie the chain prefers the cluster
+// before it and then the one with the lowest index. The merges found are
+// replayed in the order the greedy algorithm would make them with the
+// LowestIndex policy. If the replay cannot guarantee the same merges
+// this will return false, the greedy algorithm should be used instead.
+// If the fit is cancelled this will return the error of the context
+func nnchain(m *distance.Matrix, size []int, linkage Linkage, t tracker) ([]step, bool, error) {
+	original, initial := m, size
+	if linkage.Squared() {
+		m = lancewilliams.Square(m)
+	} else {
+		m = m.Copy()
+	}
+	size = append([]int(nil), size...)
+
+	n := m.Len()
+	steps := make([]step, 0, n)
+	chain := make([]int, 0, n)
+	for len(steps) < n-1 {
+		if len(chain) == 0 {
+			for i := 0; i < n; i++ {
+				if size[i] != 0 {
+					chain = append(chain, i)
+					break
+				}
+			}
+		}
+
+		// grow the chain until we find a reciprocal nearest neighbors pair
+		for {
+			a, previous := chain[len(chain)-1], -1
+			if len(chain) > 1 {
+				previous = chain[len(chain)-2]
+			}
+
+			b, best := -1, 0.0
+			for k := 0; k < n; k++ {
+				if size[k] == 0 || k == a {
+					continue
+				}
+
+				d := lancewilliams.Height(m.At(a, k), linkage)
+				if b == -1 || d < best || (d == best && k == previous) {
+					b, best = k, d
+				}
+			}
+
+			if b == previous {
+				break
+			}
+			chain = append(chain, b)
+			if len(chain) > n {
+				// the rounding broke the reducibility property
+				return nil, false, nil
+			}
+		}
+
+		// merge the last two clusters of the chain, the merged cluster
+		// will take the place of the one with the lowest index
+		i, j := chain[len(chain)-2], chain[len(chain)-1]
+		chain = chain[:len(chain)-2]
+		if j < i {
+			i, j = j, i
+		}
+
+		steps = append(steps, step{
+			first:    i,
+			second:   j,
+			distance: lancewilliams.Height(m.At(i, j), linkage),
+		})
+
+		lancewilliams.Update(m, size, i, j, linkage)
+		if err := t.err(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	// the merges were found in the order of the chain, sort them
+	// by the distance and the clusters the greedy algorithm uses
+	sort.SliceStable(steps, func(i, j int) bool {
+		if steps[i].distance != steps[j].distance {
+			return steps[i].distance < steps[j].distance
+		}
+		if steps[i].first != steps[j].first {
+			return steps[i].first < steps[j].first
+		}
+		return steps[i].second < steps[j].second
+	})
+
+	return ordered(original, initial, steps, linkage, t)
+}
+
+// ordered replays the merges on the matrix in the same way the greedy
+// algorithm does, so the distances are computed in the same order, and
+// checks that every merge is the one the greedy algorithm would choose
+// with the LowestIndex policy. Every merge must be closer than every
+// merge after it, or tied with a higher pair of clusters, and no other
+// pair holding one of its clusters can be closer.
+// If a merge is not the one of the greedy algorithm this will return false
+func ordered(m *distance.Matrix, size []int, steps []step, linkage Linkage, t tracker) ([]step, bool, error) {
+	if linkage.Squared() {
+		m = lancewilliams.Square(m)
+	} else {
+		m = m.Copy()
+	}
+	size = append([]int(nil), size...)
+
+	// before tells if the pair (a, b) at the distance d comes before
+	// the pair (i, j) at the distance h, where a < b and i < j
+	before := func(d float64, a, b int, h float64, i, j int) bool {
+		if d != h {
+			return d < h
+		}
+		return a < i || (a == i && b < j)
+	}
+
+	n := m.Len()
+	for k := range steps {
+		s := &steps[k]
+		i, j := s.first, s.second
+		if size[i] == 0 || size[j] == 0 {
+			return nil, false, nil
+		}
+
+		h := lancewilliams.Height(m.At(i, j), linkage)
+		if k > 0 {
+			last := steps[k-1]
+			if !before(last.distance, last.first, last.second, h, i, j) {
+				return nil, false, nil
+			}
+		}
+		for c := 0; c < n; c++ {
+			if size[c] == 0 || c == i || c == j {
+				continue
+			}
+			for _, r := range [2]int{i, j} {
+				a, b := r, c
+				if b < a {
+					a, b = b, a
+				}
+				if before(lancewilliams.Height(m.At(a, b), linkage), a, b, h, i, j) {
+					return nil, false, nil
+				}
+			}
+		}
+
+		s.distance, s.size = h, size[i]+size[j]
+		lancewilliams.Update(m, size, i, j, linkage)
+		if err := t.err(); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return steps, true, nil
+}
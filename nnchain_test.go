@@ -0,0 +1,138 @@
+package cluster_test
+
+import (
+	"math/rand"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/averagelinkage"
+	"github.com/hoenirvili/cluster/completelinkage"
+	"github.com/hoenirvili/cluster/dimension"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/dimension/two"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/singlelinkage"
+	"github.com/hoenirvili/cluster/wardlinkage"
+	"github.com/hoenirvili/cluster/weightedlinkage"
+	gc "gopkg.in/check.v1"
+)
+
+type nnchainSuite struct{}
+
+var _ = gc.Suite(&nnchainSuite{})
+
+// greedyLinkage hides the Reducible method of the linkage
+// so the greedy algorithm is always used
+type greedyLinkage struct {
+	cluster.Linkage
+}
+
+func (nn nnchainSuite) randomPoints(seed int64, n int) []dimension.Distancer {
+	r := rand.New(rand.NewSource(seed))
+	x, y := make([]float64, n), make([]float64, n)
+	for i := 0; i < n; i++ {
+		x[i] = float64(r.Intn(100000)) / 100
+		y[i] = float64(r.Intn(100000)) / 100
+	}
+
+	return two.NewDistances(x, y)
+}
+
+func (nn nnchainSuite) randomDistances(seed int64, n int) []distance.Distance {
+	return distance.NewDistances(nn.randomPoints(seed, n))
+}
+
+func (nn nnchainSuite) reducible() []cluster.Linkage {
+	return []cluster.Linkage{
+		singlelinkage.NewSingleLinkage(),
+		completelinkage.NewCompleteLinkage(),
+		averagelinkage.NewAverageLinkage(),
+		weightedlinkage.NewWeightedLinkage(),
+		wardlinkage.NewWardLinkage(),
+	}
+}
+
+func (nn nnchainSuite) TestNNChainMatchesGreedy(c *gc.C) {
+	for seed := int64(1); seed <= 5; seed++ {
+		distances := nn.randomDistances(seed, 30)
+		for _, linkage := range nn.reducible() {
+			d, ok := cluster.NNChainDendrogram(distances, linkage)
+			c.Assert(ok, gc.Equals, true)
+			c.Assert(d, gc.DeepEquals, cluster.GreedyDendrogram(distances, linkage))
+
+			for k := len(distances); k > 0; k-- {
+				expected := cluster.FitWith(distances, greedyLinkage{linkage}, k)
+				clusters := cluster.FitWith(distances, linkage, k)
+				c.Assert(clusters, gc.DeepEquals, expected)
+			}
+		}
+	}
+}
+
+func (nn nnchainSuite) TestNNChainTies(c *gc.C) {
+	// the one dimension points have a lot of ties
+	distances := clusterSuite{}.oneDistances(c)
+	linkage := averagelinkage.NewAverageLinkage()
+	for k := len(distances); k > 0; k-- {
+		expected := cluster.FitWith(distances, greedyLinkage{linkage}, k)
+		clusters := cluster.FitWith(distances, linkage, k)
+		c.Assert(clusters, gc.DeepEquals, expected)
+	}
+}
+
+func (nn nnchainSuite) TestNNChainRandom(c *gc.C) {
+	// a few hundred points have distances and merges that tie
+	// after they are rounded, the chain must handle them
+	distances := nn.randomDistances(1, 300)
+	for _, linkage := range nn.reducible() {
+		d, ok := cluster.NNChainDendrogram(distances, linkage)
+		c.Assert(ok, gc.Equals, true)
+		c.Assert(d, gc.DeepEquals, cluster.GreedyDendrogram(distances, linkage))
+	}
+}
+
+func (nn nnchainSuite) TestNNChainOrder(c *gc.C) {
+	// the chain starts from x1 and reaches the tie between x2, x3 and
+	// x3, x4 from x4, the greedy algorithm merges x2 and x3 first
+	distances := distance.NewDistances(one.NewDistances(10, 0, 1, 2))
+	linkage := completelinkage.NewCompleteLinkage()
+	if d, ok := cluster.NNChainDendrogram(distances, linkage); ok {
+		c.Assert(d, gc.DeepEquals, cluster.GreedyDendrogram(distances, linkage))
+	}
+	for k := len(distances); k > 0; k-- {
+		expected := cluster.FitWith(distances, greedyLinkage{linkage}, k)
+		c.Assert(cluster.FitWith(distances, linkage, k), gc.DeepEquals, expected)
+	}
+}
+
+func (nn nnchainSuite) TestFlexibleMatchesGreedy(c *gc.C) {
+	for _, beta := range []float64{-0.25, 0, 0.5} {
+		linkage := lancewilliams.NewFlexible(beta)
+		for seed := int64(1); seed <= 100; seed++ {
+			distances := nn.randomDistances(seed, 12)
+			for k := len(distances); k > 0; k-- {
+				expected := cluster.FitWith(distances, greedyLinkage{linkage}, k)
+				clusters := cluster.FitWith(distances, linkage, k)
+				c.Assert(clusters, gc.DeepEquals, expected, gc.Commentf("beta %v seed %d", beta, seed))
+			}
+		}
+	}
+}
+
+// benchmarks
+
+func (nn nnchainSuite) BenchmarkFitNNChain(c *gc.C) {
+	distances := nn.randomDistances(1, 200)
+	linkage := averagelinkage.NewAverageLinkage()
+	for i := 0; i < c.N; i++ {
+		cluster.FitWith(distances, linkage, 1)
+	}
+}
+
+func (nn nnchainSuite) BenchmarkFitGreedy(c *gc.C) {
+	distances := nn.randomDistances(1, 200)
+	linkage := greedyLinkage{averagelinkage.NewAverageLinkage()}
+	for i := 0; i < c.N; i++ {
+		cluster.FitWith(distances, linkage, 1)
+	}
+}
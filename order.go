@@ -0,0 +1,206 @@
+package cluster
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hoenirvili/cluster/distance"
+)
+
+// tree returns the two children of every node of the dendrogram, the nodes
+// from 0 to n-1 are the leaves and the node n+i is created by the step i.
+// The children of the leaves are -1. This also returns the roots of the
+// dendrogram, more than one if the dendrogram is not complete, in the order
+// of their first leaf
+func (d Dendrogram) tree(steps []step) ([][2]int, []int) {
+	n := len(d.Leaves)
+
+	// node holds for every cluster the node that represents it
+	node := make([]int, n)
+	first := make([]int, n)
+	children := make([][2]int, n, n+len(steps))
+	for i := range node {
+		node[i], first[i] = i, i
+		children[i] = [2]int{-1, -1}
+	}
+
+	for i, s := range steps {
+		children = append(children, [2]int{node[s.first], node[s.second]})
+		node[s.first], node[s.second] = n+i, -1
+		if first[s.second] < first[s.first] {
+			first[s.first] = first[s.second]
+		}
+	}
+
+	roots := make([]int, 0)
+	slots := make([]int, 0)
+	for i := range node {
+		if node[i] != -1 {
+			slots = append(slots, i)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		return first[slots[i]] < first[slots[j]]
+	})
+	for _, slot := range slots {
+		roots = append(roots, node[slot])
+	}
+
+	return children, roots
+}
+
+// members returns the leaves of every node of the tree,
+// the leaves of the first child always come first
+func members(children [][2]int) [][]int {
+	leaves := make([][]int, len(children))
+	for v, c := range children {
+		if c[0] == -1 {
+			leaves[v] = []int{v}
+			continue
+		}
+		leaves[v] = append(append([]int{}, leaves[c[0]]...), leaves[c[1]]...)
+	}
+
+	return leaves
+}
+
+// opposite returns the positions, from lo up to hi, of the leaves of the
+// node where an ordering of the node that starts with its i-th leaf can end.
+// The ordering must go through both children of the node so it ends in the
+// child that does not hold the i-th leaf. A leaf starts and ends its own ordering
+func opposite(leaves [][]int, children [][2]int, node, i int) (lo, hi int) {
+	if children[node][0] == -1 {
+		return 0, 1
+	}
+
+	split := len(leaves[children[node][0]])
+	if i < split {
+		return split, len(leaves[node])
+	}
+
+	return 0, split
+}
+
+// Order returns the index of every leaf in the order the dendrogram
+// draws them, the leaves of the first cluster of a merge come
+// before the leaves of the second one
+func (d Dendrogram) Order() []int {
+	children, roots := d.tree(d.steps())
+	leaves := members(children)
+	order := make([]int, 0, len(d.Leaves))
+	for _, root := range roots {
+		order = append(order, leaves[root]...)
+	}
+
+	return order
+}
+
+// OptimalOrder returns the index of every leaf in the order that
+// minimizes the sum of the distances between adjacent leaves, only
+// by flipping the two clusters of the merges (Bar-Joseph et al.).
+// The rows of the table of distances must follow the order of the leaves,
+// same as the table the dendrogram was fit on. This needs O(n^3) time.
+// If the table does not match the leaves this will return nil
+func (d Dendrogram) OptimalOrder(points []distance.Distance) []int {
+	n := len(d.Leaves)
+	if len(points) != n || n == 0 {
+		return nil
+	}
+
+	m := distance.Condense(points)
+	children, roots := d.tree(d.steps())
+	leaves := members(children)
+
+	// cost holds the minimum cost of the ordering of the lowest common
+	// cluster of u and w that starts with u and ends with w. In that ordering
+	// x is the last leaf of the child cluster holding u and y is the first
+	// leaf of the child cluster holding w. Like cost, end only stores the
+	// upper triangle, x and y of the pair with u lower than w
+	cost := distance.NewZeroMatrix(n)
+	end := make([][2]int, n*(n-1)/2)
+	index := func(u, w int) int {
+		return u*(2*n-u-1)/2 + w - u - 1
+	}
+	setEnd := func(u, w, x, y int) {
+		if u < w {
+			end[index(u, w)] = [2]int{x, y}
+			return
+		}
+		end[index(w, u)] = [2]int{y, x}
+	}
+	getEnd := func(u, w int) (x, y int) {
+		if u < w {
+			e := end[index(u, w)]
+			return e[0], e[1]
+		}
+		e := end[index(w, u)]
+		return e[1], e[0]
+	}
+
+	for v := n; v < len(children); v++ {
+		l, r := children[v][0], children[v][1]
+		left, right := leaves[l], leaves[r]
+
+		// best[a][b] is the minimum cost of an ordering of the left cluster
+		// starting with left[a] plus the distance to the leaf right[b]
+		best := make([][]float64, len(left))
+		last := make([][]int, len(left))
+		for a, u := range left {
+			best[a] = make([]float64, len(right))
+			last[a] = make([]int, len(right))
+			for b, k := range right {
+				best[a][b] = math.Inf(1)
+				lo, hi := opposite(leaves, children, l, a)
+				for _, x := range left[lo:hi] {
+					if c := cost.At(u, x) + m.At(x, k); c < best[a][b] {
+						best[a][b], last[a][b] = c, x
+					}
+				}
+			}
+		}
+
+		for a, u := range left {
+			for c, w := range right {
+				lowest, x, y := math.Inf(1), -1, -1
+				lo, hi := opposite(leaves, children, r, c)
+				for b := lo; b < hi; b++ {
+					k := right[b]
+					if total := best[a][b] + cost.At(k, w); total < lowest {
+						lowest, x, y = total, last[a][b], k
+					}
+				}
+				cost.Set(u, w, lowest)
+				setEnd(u, w, x, y)
+			}
+		}
+	}
+
+	var ordering func(u, w int) []int
+	ordering = func(u, w int) []int {
+		if u == w {
+			return []int{u}
+		}
+		x, y := getEnd(u, w)
+		return append(ordering(u, x), ordering(y, w)...)
+	}
+
+	order := make([]int, 0, n)
+	for _, root := range roots {
+		if children[root][0] == -1 {
+			order = append(order, root)
+			continue
+		}
+
+		lowest, u, w := math.Inf(1), -1, -1
+		for _, a := range leaves[children[root][0]] {
+			for _, b := range leaves[children[root][1]] {
+				if c := cost.At(a, b); c < lowest {
+					lowest, u, w = c, a, b
+				}
+			}
+		}
+		order = append(order, ordering(u, w)...)
+	}
+
+	return order
+}
@@ -0,0 +1,114 @@
+package cluster_test
+
+import (
+	"reflect"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/util"
+	gc "gopkg.in/check.v1"
+)
+
+type orderSuite struct{}
+
+var _ = gc.Suite(&orderSuite{})
+
+// cost returns the sum of the distances between adjacent leaves
+func (o orderSuite) cost(m *distance.Matrix, order []int) float64 {
+	sum := 0.0
+	for i := 1; i < len(order); i++ {
+		sum += m.At(order[i-1], order[i])
+	}
+
+	return util.Round(sum, 4)
+}
+
+// orderings returns every leaf order that can be drawn by
+// flipping the clusters of the merges of the dendrogram
+func (o orderSuite) orderings(d *cluster.Dendrogram) [][]int {
+	all := make(map[set.Set][][]int)
+	for i, leaf := range d.Leaves {
+		all[leaf] = [][]int{{i}}
+	}
+
+	for _, merge := range d.Merges {
+		merged := merge.First
+		merged.Add(merge.Second)
+		for _, a := range all[merge.First] {
+			for _, b := range all[merge.Second] {
+				ab := append(append([]int{}, a...), b...)
+				ba := append(append([]int{}, b...), a...)
+				all[merged] = append(all[merged], ab, ba)
+			}
+		}
+	}
+
+	root := d.Merges[len(d.Merges)-1].First
+	root.Add(d.Merges[len(d.Merges)-1].Second)
+	return all[root]
+}
+
+func (o orderSuite) TestOrder(c *gc.C) {
+	d := cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3", "x4"},
+		Merges: []cluster.Merge{
+			{First: "x2", Second: "x4", Distance: 1, Size: 2},
+			{First: "x1", Second: "x3", Distance: 2, Size: 2},
+			{First: "x1,x3", Second: "x2,x4", Distance: 3, Size: 4},
+		},
+	}
+	c.Assert(d.Order(), gc.DeepEquals, []int{0, 2, 1, 3})
+
+	// the clusters that were never merged are drawn by their first leaf
+	d.Merges = d.Merges[:1]
+	c.Assert(d.Order(), gc.DeepEquals, []int{0, 1, 3, 2})
+}
+
+func (o orderSuite) TestOptimalOrder(c *gc.C) {
+	for seed := int64(1); seed <= 5; seed++ {
+		distances := nnchainSuite{}.randomDistances(seed, 7)
+		m := distance.Condense(distances)
+		for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+			d := cluster.FitDendrogram(distances, s)
+			order := d.OptimalOrder(distances)
+
+			// the order must be one of the orders of the dendrogram
+			// with the minimum cost of all of them
+			orderings := o.orderings(d)
+			found, lowest := false, o.cost(m, orderings[0])
+			for _, ordering := range orderings {
+				if o.cost(m, ordering) < lowest {
+					lowest = o.cost(m, ordering)
+				}
+				found = found || reflect.DeepEqual(ordering, order)
+			}
+			c.Assert(found, gc.Equals, true)
+			c.Assert(o.cost(m, order), gc.Equals, lowest)
+			c.Assert(o.cost(m, order) <= o.cost(m, d.Order()), gc.Equals, true)
+		}
+	}
+}
+
+func (o orderSuite) TestOptimalOrderLine(c *gc.C) {
+	// the points on a line are ordered from one end to the other
+	distances := clusterSuite{}.oneDistances(c)
+	d := cluster.FitDendrogram(distances, cluster.AverageLinkage)
+	order := d.OptimalOrder(distances)
+	reversed := []int{7, 6, 5, 4, 3, 2, 1, 0}
+	if order[0] != 0 {
+		c.Assert(order, gc.DeepEquals, reversed)
+	} else {
+		c.Assert(order, gc.DeepEquals, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	}
+}
+
+func (o orderSuite) TestOptimalOrderInvalid(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	d := cluster.FitDendrogram(distances, cluster.SingleLinkage)
+	c.Assert(d.OptimalOrder(distances[:3]), gc.IsNil)
+	c.Assert(cluster.Dendrogram{}.OptimalOrder(nil), gc.IsNil)
+
+	one := cluster.FitDendrogram(distances[7:], cluster.SingleLinkage)
+	c.Assert(one.OptimalOrder(distances[7:]), gc.DeepEquals, []int{0})
+}
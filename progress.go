@@ -0,0 +1,34 @@
+package cluster
+
+import "context"
+
+// Progress is called after every merge with the number
+// of clusters left and the distance of the last merge
+type Progress func(clusters int, distance float64)
+
+// tracker follows the merges made while fitting, reporting
+// the progress and checking if the fit was cancelled.
+// The zero value never reports and never cancels
+type tracker struct {
+	ctx      context.Context
+	progress Progress
+}
+
+// merged reports the merge and returns the error of the
+// context if the fit was cancelled
+func (t tracker) merged(clusters int, distance float64) error {
+	if t.progress != nil {
+		t.progress(clusters, distance)
+	}
+
+	return t.err()
+}
+
+// err returns the error of the context if the fit was cancelled
+func (t tracker) err() error {
+	if t.ctx == nil {
+		return nil
+	}
+
+	return t.ctx.Err()
+}
@@ -0,0 +1,114 @@
+package cluster_test
+
+import (
+	"context"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	gc "gopkg.in/check.v1"
+)
+
+type progressSuite struct{}
+
+var _ = gc.Suite(&progressSuite{})
+
+func (p progressSuite) TestFitContext(c *gc.C) {
+	tables := [][]distance.Distance{
+		nnchainSuite{}.randomDistances(3, 20),
+		// the one dimension points have a lot of ties
+		clusterSuite{}.oneDistances(c),
+		distance.NewDistances(one.NewDistances(0, 1, 10, 10.5, 30, 33)),
+		distance.NewDistances(one.NewDistances(1, 2, 3, 4, 5, 6)),
+	}
+
+	for _, distances := range tables {
+		for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+			clusters, heights := []int{}, []float64{}
+			progress := func(n int, distance float64) {
+				clusters = append(clusters, n)
+				heights = append(heights, distance)
+			}
+
+			got, err := cluster.FitContext(context.Background(), distances, s, 4, progress)
+			c.Assert(err, gc.IsNil)
+			c.Assert(got, gc.DeepEquals, cluster.Fit(distances, s, 4))
+
+			// every merge is reported once, in the order the merges are made
+			d := cluster.FitDendrogram(distances, s)
+			c.Assert(clusters, gc.HasLen, len(distances)-1)
+			for i, n := range clusters {
+				c.Assert(n, gc.Equals, len(distances)-i-1)
+				c.Assert(heights[i], gc.Equals, d.Merges[i].Distance)
+				if i > 0 && d.Monotonic() {
+					c.Assert(heights[i] >= heights[i-1], gc.Equals, true)
+				}
+			}
+		}
+	}
+}
+
+func (p progressSuite) TestFitContextCancel(c *gc.C) {
+	distances := nnchainSuite{}.randomDistances(3, 20)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		merges := 0
+		progress := func(int, float64) {
+			merges++
+			if merges == 3 {
+				cancel()
+			}
+		}
+
+		got, err := cluster.FitContext(ctx, distances, s, 1, progress)
+		c.Assert(err, gc.Equals, context.Canceled)
+		c.Assert(got, gc.IsNil)
+		c.Assert(merges, gc.Equals, 3)
+	}
+}
+
+// checkedContext counts how many times the fit checks
+// if the context was cancelled
+type checkedContext struct {
+	context.Context
+	checks int
+}
+
+func (ctx *checkedContext) Err() error {
+	ctx.checks++
+	return ctx.Context.Err()
+}
+
+func (p progressSuite) TestFitContextReportsEarly(c *gc.C) {
+	distances := nnchainSuite{}.randomDistances(3, 50)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		parent, cancel := context.WithCancel(context.Background())
+		ctx := &checkedContext{Context: parent}
+		merges, checks := 0, 0
+		progress := func(int, float64) {
+			merges++
+			checks = ctx.checks
+			cancel()
+		}
+
+		got, err := cluster.FitContext(ctx, distances, s, 1, progress)
+		c.Assert(err, gc.Equals, context.Canceled)
+		c.Assert(got, gc.IsNil)
+
+		// the context is checked between merges, the first merge is
+		// reported before any other merge is computed and the fit
+		// stops there
+		c.Assert(merges, gc.Equals, 1)
+		c.Assert(checks, gc.Equals, 1)
+	}
+}
+
+func (p progressSuite) TestFitContextCancelled(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	distances := clusterSuite{}.oneDistances(c)
+	got, err := cluster.FitContext(ctx, distances, cluster.AverageLinkage, 2, nil)
+	c.Assert(err, gc.Equals, context.Canceled)
+	c.Assert(got, gc.IsNil)
+}
@@ -0,0 +1,241 @@
+package cluster
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+)
+
+// LinkageMatrix returns the merges of the dendrogram as the (n-1)x4 linkage
+// matrix of SciPy. The row i merges the clusters of the first two columns,
+// the leaf i is the cluster i and the merge of the row i is the cluster n+i,
+// at the distance of the third column into a cluster with the number of
+// points of the fourth column. The lower cluster is always the first one.
+// If the dendrogram is not complete this will return an error
+func (d Dendrogram) LinkageMatrix() ([][4]float64, error) {
+	n := len(d.Leaves)
+	steps := d.steps()
+	if n == 0 || len(steps) != n-1 {
+		return nil, fmt.Errorf("cluster: %d merges for %d leaves, the dendrogram is not complete", len(steps), n)
+	}
+
+	children, _ := d.tree(steps)
+	z := make([][4]float64, len(steps), len(steps))
+	for i, s := range steps {
+		a, b := children[n+i][0], children[n+i][1]
+		if b < a {
+			a, b = b, a
+		}
+		z[i] = [4]float64{float64(a), float64(b), s.distance, float64(s.size)}
+	}
+
+	return z, nil
+}
+
+// FromLinkageMatrix returns the dendrogram of the linkage matrix of SciPy,
+// the n leaves are named x1, x2, x3, ... same as distance.NewDistances names
+// them in the order of the clusters of the matrix.
+// If a row does not merge two clusters not merged yet or its number of points
+// does not match them this will return a LinkageMatrixError
+func FromLinkageMatrix(z [][4]float64) (*Dendrogram, error) {
+	n := len(z) + 1
+	leaves := make([]set.Set, n, n)
+	lowest := make([]int, 2*n-1, 2*n-1)
+	size := make([]int, 2*n-1, 2*n-1)
+	used := make([]bool, 2*n-1, 2*n-1)
+	for i := range leaves {
+		leaves[i] = distance.Name(i)
+		lowest[i], size[i] = i, 1
+	}
+
+	steps := make([]step, 0, len(z))
+	for i, row := range z {
+		var clusters [2]int
+		for c := range clusters {
+			id := row[c]
+			if id != math.Trunc(id) || id < 0 || id >= float64(n+i) {
+				return nil, LinkageMatrixError{Row: i, Reason: "unknown cluster " + strconv.FormatFloat(id, 'f', -1, 64)}
+			}
+			clusters[c] = int(id)
+			if used[clusters[c]] {
+				return nil, LinkageMatrixError{Row: i, Reason: fmt.Sprintf("cluster %d is already merged", clusters[c])}
+			}
+		}
+		a, b := clusters[0], clusters[1]
+		if a == b {
+			return nil, LinkageMatrixError{Row: i, Reason: fmt.Sprintf("cluster %d is merged with itself", a)}
+		}
+		if math.IsNaN(row[2]) || row[2] < 0 {
+			return nil, LinkageMatrixError{Row: i, Reason: "invalid distance " + strconv.FormatFloat(row[2], 'f', -1, 64)}
+		}
+		if row[3] != float64(size[a]+size[b]) {
+			return nil, LinkageMatrixError{Row: i, Reason: fmt.Sprintf("expected %d points", size[a]+size[b])}
+		}
+
+		used[a], used[b] = true, true
+		v := n + i
+		first, second := lowest[a], lowest[b]
+		if second < first {
+			first, second = second, first
+		}
+		lowest[v], size[v] = first, size[a]+size[b]
+
+		s := step{
+			first:    first,
+			second:   second,
+			distance: row[2],
+			size:     size[v],
+		}
+		if last := len(steps) - 1; last >= 0 {
+			s.inversion = row[2] < steps[last].distance
+		}
+		steps = append(steps, s)
+	}
+
+	return &Dendrogram{
+		Leaves: leaves,
+		Merges: merges(leaves, steps),
+	}, nil
+}
+
+// WriteLinkageCSV writes the linkage matrix as csv, one row on every line,
+// the same text numpy.loadtxt reads with a comma as delimiter
+func WriteLinkageCSV(w io.Writer, z [][4]float64) error {
+	b := bufio.NewWriter(w)
+	for _, row := range z {
+		for c, x := range row {
+			if c > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+		}
+		b.WriteByte('\n')
+	}
+
+	return b.Flush()
+}
+
+// ReadLinkageCSV reads the linkage matrix from csv, one row on every line.
+// If a line does not hold 4 numbers this will return an error
+func ReadLinkageCSV(r io.Reader) ([][4]float64, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	z := make([][4]float64, len(records), len(records))
+	for i, record := range records {
+		for c, field := range record {
+			x, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("cluster: invalid number %q at line %d", field, i+1)
+			}
+			z[i][c] = x
+		}
+	}
+
+	return z, nil
+}
+
+// npyMagic is the start of every file in the npy format
+const npyMagic = "\x93NUMPY"
+
+// WriteLinkageNpy writes the linkage matrix in the npy format of numpy,
+// a little endian float64 array of shape (n-1, 4) that numpy.load reads
+func WriteLinkageNpy(w io.Writer, z [][4]float64) error {
+	header := fmt.Sprintf("{'descr': '<f8', 'fortran_order': False, 'shape': (%d, 4), }", len(z))
+	// the header ends with a new line and the data starts
+	// at a multiple of 64 bytes from the start of the file
+	total := len(npyMagic) + 4 + len(header) + 1
+	header += strings.Repeat(" ", (64-total%64)%64) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.Write([]byte{1, 0})
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	binary.Write(&buf, binary.LittleEndian, z)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// the fields of the header of a npy file
+var (
+	npyDescr   = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape   = regexp.MustCompile(`'shape'\s*:\s*\(\s*(\d+)\s*,\s*(\d+)\s*,?\s*\)`)
+)
+
+// ReadLinkageNpy reads the linkage matrix from the npy format of numpy,
+// the array must be a float64 array of shape (n-1, 4).
+// If the file is not in this format this will return an error
+func ReadLinkageNpy(r io.Reader) ([][4]float64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < len(npyMagic)+4 || string(data[:len(npyMagic)]) != npyMagic {
+		return nil, fmt.Errorf("cluster: not a npy file")
+	}
+
+	data = data[len(npyMagic):]
+	var length int
+	switch data[0] {
+	case 1:
+		length, data = int(binary.LittleEndian.Uint16(data[2:4])), data[4:]
+	case 2, 3:
+		if len(data) < 6 {
+			return nil, fmt.Errorf("cluster: not a npy file")
+		}
+		length, data = int(binary.LittleEndian.Uint32(data[2:6])), data[6:]
+	default:
+		return nil, fmt.Errorf("cluster: unknown npy version %d", data[0])
+	}
+	if len(data) < length {
+		return nil, fmt.Errorf("cluster: truncated npy header")
+	}
+	header, data := string(data[:length]), data[length:]
+
+	match := npyDescr.FindStringSubmatch(header)
+	if match == nil || match[1] != "<f8" {
+		return nil, fmt.Errorf("cluster: npy array is not a little endian float64 array")
+	}
+	dims := npyShape.FindStringSubmatch(header)
+	if dims == nil || dims[2] != "4" {
+		return nil, fmt.Errorf("cluster: npy array is not of shape (n, 4)")
+	}
+	rows, err := strconv.Atoi(dims[1])
+	if err != nil || len(data) != rows*4*8 {
+		return nil, fmt.Errorf("cluster: npy array does not hold %s rows", dims[1])
+	}
+
+	values := make([]float64, rows*4, rows*4)
+	binary.Read(bytes.NewReader(data), binary.LittleEndian, values)
+	transposed := npyFortran.FindStringSubmatch(header)
+	z := make([][4]float64, rows, rows)
+	for i := range z {
+		for c := range z[i] {
+			if transposed != nil && transposed[1] == "True" {
+				z[i][c] = values[c*rows+i]
+				continue
+			}
+			z[i][c] = values[i*4+c]
+		}
+	}
+
+	return z, nil
+}
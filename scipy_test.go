@@ -0,0 +1,135 @@
+package cluster_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type scipySuite struct{}
+
+var _ = gc.Suite(&scipySuite{})
+
+func (s scipySuite) TestLinkageMatrix(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	d := cluster.FitDendrogram(points, cluster.SingleLinkage)
+	z, err := d.LinkageMatrix()
+	c.Assert(err, gc.IsNil)
+	// the same matrix scipy.cluster.hierarchy.linkage returns
+	c.Assert(z, gc.DeepEquals, [][4]float64{
+		{0, 1, 1, 2},
+		{2, 4, 2, 3},
+		{3, 5, 4, 4},
+	})
+
+	d = &cluster.Dendrogram{
+		Leaves: []set.Set{"x1", "x2", "x3"},
+		Merges: []cluster.Merge{{First: "x1", Second: "x2", Distance: 1, Size: 2}},
+	}
+	_, err = d.LinkageMatrix()
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 merges for 3 leaves, the dendrogram is not complete")
+}
+
+func (s scipySuite) TestLinkageMatrixRoundTrip(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0, 0.9, 3))
+	for st := cluster.SingleLinkage; st <= cluster.WeightedLinkage; st++ {
+		d := cluster.FitDendrogram(points, st)
+		z, err := d.LinkageMatrix()
+		c.Assert(err, gc.IsNil)
+
+		var text, npy bytes.Buffer
+		c.Assert(cluster.WriteLinkageCSV(&text, z), gc.IsNil)
+		c.Assert(cluster.WriteLinkageNpy(&npy, z), gc.IsNil)
+		fromText, err := cluster.ReadLinkageCSV(&text)
+		c.Assert(err, gc.IsNil)
+		c.Assert(fromText, gc.DeepEquals, z)
+		fromNpy, err := cluster.ReadLinkageNpy(&npy)
+		c.Assert(err, gc.IsNil)
+		c.Assert(fromNpy, gc.DeepEquals, z)
+
+		read, err := cluster.FromLinkageMatrix(z)
+		c.Assert(err, gc.IsNil)
+		c.Assert(read, gc.DeepEquals, d)
+		for k := 1; k <= len(points); k++ {
+			c.Assert(read.Cut(k), gc.DeepEquals, cluster.Fit(points, st, k))
+		}
+	}
+}
+
+func (s scipySuite) TestFromLinkageMatrixErrors(c *gc.C) {
+	tests := []struct {
+		z       [][4]float64
+		message string
+	}{
+		{[][4]float64{{0, 3, 1, 2}}, "cluster: invalid linkage matrix at row 0, unknown cluster 3"},
+		{[][4]float64{{0, 0.5, 1, 2}}, "cluster: invalid linkage matrix at row 0, unknown cluster 0.5"},
+		{[][4]float64{{0, 1, 1, 2}, {1, 2, 2, 2}}, "cluster: invalid linkage matrix at row 1, cluster 1 is already merged"},
+		{[][4]float64{{1, 1, 1, 2}, {0, 2, 2, 2}}, "cluster: invalid linkage matrix at row 0, cluster 1 is merged with itself"},
+		{[][4]float64{{0, 1, -1, 2}}, "cluster: invalid linkage matrix at row 0, invalid distance -1"},
+		{[][4]float64{{0, 1, 1, 2}, {2, 3, 2, 2}}, "cluster: invalid linkage matrix at row 1, expected 3 points"},
+	}
+
+	for _, test := range tests {
+		d, err := cluster.FromLinkageMatrix(test.z)
+		c.Assert(err, gc.ErrorMatches, test.message)
+		c.Assert(err, gc.FitsTypeOf, cluster.LinkageMatrixError{})
+		c.Assert(d, gc.IsNil)
+	}
+}
+
+func (s scipySuite) TestLinkageCSV(c *gc.C) {
+	z := [][4]float64{{0, 1, 0.5, 2}, {2, 3, 1.25, 3}}
+	var buf bytes.Buffer
+	c.Assert(cluster.WriteLinkageCSV(&buf, z), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "0,1,0.5,2\n2,3,1.25,3\n")
+
+	// the text numpy.savetxt writes
+	read, err := cluster.ReadLinkageCSV(strings.NewReader(
+		"0.000000000000000000e+00,1.000000000000000000e+00,5.000000000000000000e-01,2.000000000000000000e+00\n" +
+			"2.000000000000000000e+00, 3.000000000000000000e+00, 1.250000000000000000e+00, 3.000000000000000000e+00\n"))
+	c.Assert(err, gc.IsNil)
+	c.Assert(read, gc.DeepEquals, z)
+
+	_, err = cluster.ReadLinkageCSV(strings.NewReader("0,1,2\n"))
+	c.Assert(err, gc.NotNil)
+	_, err = cluster.ReadLinkageCSV(strings.NewReader("0,1,a,2\n"))
+	c.Assert(err, gc.ErrorMatches, `cluster: invalid number "a" at line 1`)
+}
+
+func (s scipySuite) TestLinkageNpy(c *gc.C) {
+	z := [][4]float64{{0, 1, 0.5, 2}, {2, 3, 1.25, 3}}
+	var buf bytes.Buffer
+	c.Assert(cluster.WriteLinkageNpy(&buf, z), gc.IsNil)
+	data := buf.Bytes()
+	c.Assert(string(data[:8]), gc.Equals, "\x93NUMPY\x01\x00")
+	header := int(binary.LittleEndian.Uint16(data[8:10]))
+	c.Assert((10+header)%64, gc.Equals, 0)
+	c.Assert(strings.TrimRight(string(data[10:10+header]), " \n"), gc.Equals,
+		"{'descr': '<f8', 'fortran_order': False, 'shape': (2, 4), }")
+	c.Assert(len(data), gc.Equals, 10+header+2*4*8)
+
+	// an array in fortran order holds the columns one after the other
+	fortran := "{'descr': '<f8', 'fortran_order': True, 'shape': (2, 4), }\n"
+	var npy bytes.Buffer
+	npy.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&npy, binary.LittleEndian, uint16(len(fortran)))
+	npy.WriteString(fortran)
+	binary.Write(&npy, binary.LittleEndian, []float64{0, 2, 1, 3, 0.5, 1.25, 2, 3})
+	read, err := cluster.ReadLinkageNpy(&npy)
+	c.Assert(err, gc.IsNil)
+	c.Assert(read, gc.DeepEquals, z)
+
+	_, err = cluster.ReadLinkageNpy(strings.NewReader("0,1,0.5,2\n"))
+	c.Assert(err, gc.ErrorMatches, "cluster: not a npy file")
+	integers := strings.Replace(string(data), "<f8", "<i8", 1)
+	_, err = cluster.ReadLinkageNpy(strings.NewReader(integers))
+	c.Assert(err, gc.ErrorMatches, "cluster: npy array is not a little endian float64 array")
+	_, err = cluster.ReadLinkageNpy(bytes.NewReader(data[:len(data)-8]))
+	c.Assert(err, gc.ErrorMatches, "cluster: npy array does not hold 2 rows")
+}
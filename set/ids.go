@@ -0,0 +1,104 @@
+package set
+
+import (
+	"sort"
+	"strings"
+)
+
+// IDs describes a set of points by their indices, the integer
+// counterpart of Set. The indices will be always sorted in increasing order
+type IDs []int
+
+// NewIDs create a cluster from the given point indices
+func NewIDs(ids ...int) IDs {
+	s := make(IDs, 0, len(ids))
+	for _, id := range ids {
+		s.Add(IDs{id})
+	}
+
+	return s
+}
+
+// Add appends all the points of the given set in the set
+// If a point is already in the set it will not add it again
+func (s *IDs) Add(ids IDs) {
+	if len(ids) == 0 {
+		return
+	}
+
+	merged := make(IDs, 0, len(*s)+len(ids))
+	i, j := 0, 0
+	for i < len(*s) && j < len(ids) {
+		switch a, b := (*s)[i], ids[j]; {
+		case a < b:
+			merged = append(merged, a)
+			i++
+		case b < a:
+			merged = append(merged, b)
+			j++
+		default:
+			merged = append(merged, a)
+			i++
+			j++
+		}
+	}
+	merged = append(merged, (*s)[i:]...)
+	merged = append(merged, ids[j:]...)
+	*s = merged
+}
+
+// In returns true if all the points of the cluster are found in the set
+func (s IDs) In(ids IDs) bool {
+	i := 0
+	for _, id := range ids {
+		i += sort.SearchInts(s[i:], id)
+		if i == len(s) || s[i] != id {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Len returns the number of points in a cluster
+func (s IDs) Len() int {
+	return len(s)
+}
+
+// Members returns a copy of the point indices of the set
+func (s IDs) Members() []int {
+	members := make([]int, len(s), len(s))
+	copy(members, s)
+	return members
+}
+
+// Less returns true if the set comes before the given one,
+// the sets are ordered by their lowest point index first
+func (s IDs) Less(ids IDs) bool {
+	for i := 0; i < len(s) && i < len(ids); i++ {
+		if s[i] != ids[i] {
+			return s[i] < ids[i]
+		}
+	}
+
+	return len(s) < len(ids)
+}
+
+// Set returns the string form of the set where
+// every point index is replaced with its name
+func (s IDs) Set(names []Set) Set {
+	points := make([]string, 0, len(s))
+	for _, id := range s {
+		points = append(points, names[id].Slice()...)
+	}
+
+	nums := make(map[string]int, len(points))
+	for _, point := range points {
+		nums[point] = number(point)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return nums[points[i]] < nums[points[j]]
+	})
+
+	return Set(strings.Join(points, ","))
+}
@@ -0,0 +1,61 @@
+package set_test
+
+import (
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type idsSuite struct{}
+
+var _ = gc.Suite(&idsSuite{})
+
+func (is idsSuite) TestNewIDs(c *gc.C) {
+	c.Assert(set.NewIDs(), gc.HasLen, 0)
+	c.Assert(set.NewIDs(3, 1, 2, 1), gc.DeepEquals, set.IDs{1, 2, 3})
+}
+
+func (is idsSuite) TestAdd(c *gc.C) {
+	ids := set.NewIDs(1, 4)
+	ids.Add(set.NewIDs(0, 4, 9))
+	c.Assert(ids, gc.DeepEquals, set.IDs{0, 1, 4, 9})
+
+	ids.Add(nil)
+	c.Assert(ids, gc.DeepEquals, set.IDs{0, 1, 4, 9})
+
+	var empty set.IDs
+	empty.Add(set.NewIDs(2))
+	c.Assert(empty, gc.DeepEquals, set.IDs{2})
+}
+
+func (is idsSuite) TestIn(c *gc.C) {
+	ids := set.NewIDs(0, 1, 2)
+	c.Assert(ids.In(set.NewIDs(0)), gc.Equals, true)
+	c.Assert(ids.In(set.NewIDs(0, 1, 2)), gc.Equals, true)
+	c.Assert(ids.In(set.NewIDs(1, 2)), gc.Equals, true)
+	c.Assert(ids.In(set.NewIDs(6)), gc.Equals, false)
+	c.Assert(ids.In(set.NewIDs(0, 1, 7, 8)), gc.Equals, false)
+}
+
+func (is idsSuite) TestLenAndMembers(c *gc.C) {
+	ids := set.NewIDs(5, 2)
+	c.Assert(ids.Len(), gc.Equals, 2)
+
+	members := ids.Members()
+	c.Assert(members, gc.DeepEquals, []int{2, 5})
+	members[0] = 7
+	c.Assert(ids, gc.DeepEquals, set.IDs{2, 5})
+}
+
+func (is idsSuite) TestLess(c *gc.C) {
+	c.Assert(set.NewIDs(0, 9).Less(set.NewIDs(1)), gc.Equals, true)
+	c.Assert(set.NewIDs(1).Less(set.NewIDs(0, 9)), gc.Equals, false)
+	c.Assert(set.NewIDs(1).Less(set.NewIDs(1, 2)), gc.Equals, true)
+	c.Assert(set.NewIDs(1, 2).Less(set.NewIDs(1, 2)), gc.Equals, false)
+}
+
+func (is idsSuite) TestSet(c *gc.C) {
+	names := []set.Set{"x1", "x2", "x3,x10", "x4"}
+	c.Assert(set.NewIDs(3, 0).Set(names), gc.Equals, set.Set("x1,x4"))
+	c.Assert(set.NewIDs(2, 1).Set(names), gc.Equals, set.Set("x2,x3,x10"))
+	c.Assert(set.NewIDs().Set(names), gc.Equals, set.Set(""))
+}
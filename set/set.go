@@ -74,19 +74,53 @@ func (s Set) In(set Set) bool {
 }
 
 func (s Set) num(i int) int {
-	slice := s.Slice()
-	suffix := slice[i][1:]
+	return number(s.Slice()[i])
+}
+
+// number returns the numeric suffix of the point name
+func number(point string) int {
+	num, err := parse(point)
+	if err != nil {
+		panic(err)
+	}
+
+	return num
+}
+
+// parse parses the numeric suffix of the point name
+func parse(point string) (int, error) {
+	if point == "" {
+		return 0, fmt.Errorf("set: empty point name")
+	}
+
+	suffix := point[1:]
 	num, err := strconv.ParseInt(suffix, 10, 64)
 	if err != nil {
 		sep := strings.Split(suffix, "x")
 		num, err := strconv.ParseInt(sep[0], 10, 64)
 		if err != nil {
-			panic(err)
+			return 0, err
 		}
-		return int(num)
+		return int(num), nil
 	}
 
-	return int(num)
+	return int(num), nil
+}
+
+// Valid returns true if every point of the set is named
+// following the naming convention of the set
+func (s Set) Valid() bool {
+	if s == "" {
+		return false
+	}
+
+	for _, point := range s.Slice() {
+		if _, err := parse(point); err != nil {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Simple tests if the set has one element
@@ -95,18 +129,27 @@ func (s Set) Simple() bool {
 	return 1 == len(s.Slice())
 }
 
-// Priority returns true if the given
-// cluster is greater than the fixed one
+// Priority returns true if the fixed cluster comes before
+// the given one. The clusters are compared point by point in
+// their suffix order, a cluster comes before all the clusters
+// it is a prefix of. This defines a total order of the clusters
 func (s Set) Priority(set Set) bool {
-	if !s.Simple() || !set.Simple() {
+	if s == set {
 		return false
 	}
 
-	if s.num(0) < set.num(0) {
-		return true
+	first, second := s.Slice(), set.Slice()
+	for i := 0; i < len(first) && i < len(second); i++ {
+		a, b := number(first[i]), number(second[i])
+		if a != b {
+			return a < b
+		}
+		if first[i] != second[i] {
+			return first[i] < second[i]
+		}
 	}
 
-	return false
+	return len(first) < len(second)
 }
 
 // Len returns the number of points in a cluster
@@ -69,6 +69,22 @@ func (cs setSuite) TestPriority(c *gc.C) {
 
 	p = one.Priority(second)
 	c.Assert(p, gc.Equals, true)
+
+	// merged clusters are ordered too
+	tests := []struct {
+		first, second set.Set
+		priority      bool
+	}{
+		{"x1,x5", "x2", true},
+		{"x2", "x1,x5", false},
+		{"x1,x2", "x1,x2,x3", true},
+		{"x1,x2,x3", "x1,x2", false},
+		{"x1,x3", "x1,x10", true},
+		{"x1,x3", "x1,x3", false},
+	}
+	for _, test := range tests {
+		c.Assert(test.first.Priority(test.second), gc.Equals, test.priority)
+	}
 }
 
 func (cs setSuite) TestLen(c *gc.C) {
@@ -160,3 +176,13 @@ func (cs setSuite) TestString(c *gc.C) {
 	got := cluster.String()
 	c.Assert(got, gc.DeepEquals, expected)
 }
+
+func (cs setSuite) TestValid(c *gc.C) {
+	cls := cs.newSet(c)
+	c.Assert(cls.Valid(), gc.Equals, true)
+	c.Assert(set.Set("x1x2").Valid(), gc.Equals, true)
+
+	for _, cls := range []set.Set{"", "x", "point", "x1,,x2", "x1,y"} {
+		c.Assert(cls.Valid(), gc.Equals, false)
+	}
+}
@@ -1,9 +1,9 @@
 package singlelinkage_test
 
 import (
-	"github.com/hoenirvili/cluster/distance"
-	"github.com/hoenirvili/cluster/set"
+	"github.com/hoenirvili/cluster/lancewilliams"
 	"github.com/hoenirvili/cluster/singlelinkage"
+	"github.com/hoenirvili/cluster/util"
 	gc "gopkg.in/check.v1"
 )
 
@@ -14,48 +14,16 @@ var _ = gc.Suite(&singleLinkageSuite{})
 func (s singleLinkageSuite) TestNewSingleLinkage(c *gc.C) {
 	sl := singlelinkage.NewSingleLinkage()
 	c.Assert(sl, gc.NotNil)
+	c.Assert(sl.Squared(), gc.Equals, false)
 }
 
-func (s singleLinkageSuite) TestSingleLinkageSwap(c *gc.C) {
+func (s singleLinkageSuite) TestSingleLinkageCoefficients(c *gc.C) {
 	sl := singlelinkage.NewSingleLinkage()
-	first := distance.Distance{}
-	second := distance.Distance{}
-	sl.Swap(first, second)
-	c.Assert(first.Points, gc.IsNil)
-	c.Assert(second.Points, gc.IsNil)
+	coefficients := sl.Coefficients(1, 3, 2)
+	c.Assert(coefficients, gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.5, AlphaJ: 0.5, Beta: 0, Gamma: -0.5,
+	})
 
-	first.Points = map[set.Set]float64{
-		"x1": 3.11,
-		"x2": 6.12,
-	}
-
-	second.Points = map[set.Set]float64{
-		"x1": 0.2,
-		"x3": 0.8,
-		"x7": 0.1,
-	}
-
-	sl.Swap(first, second)
-	c.Assert(first.Points, gc.DeepEquals, map[set.Set]float64{
-		"x1": 0.2, "x2": 6.12})
-}
-
-func (s singleLinkageSuite) TestSingleLinkageRecompute(c *gc.C) {
-	on := distance.Distance{
-		Points: map[set.Set]float64{
-			"x1": 0.2,
-			"x2": 0.8,
-			"x3": 0.1,
-			"x7": 1.6,
-		},
-	}
-	based := set.Set("x2,x3")
-	sl := singlelinkage.NewSingleLinkage()
-	best, clusters := sl.Recompute(based, on)
-	c.Assert(best, gc.Equals, 0.1)
-	c.Assert(clusters, gc.DeepEquals, []set.Set{"x2"})
-
-	best, clusters = sl.Recompute(set.Set("x9,x10"), on)
-	c.Assert(best, gc.Equals, -1.0)
-	c.Assert(clusters, gc.DeepEquals, []set.Set{})
+	c.Assert(util.Round(coefficients.Distance(0.2, 0.8, 0.1), 4), gc.Equals, 0.2)
+	c.Assert(util.Round(coefficients.Distance(3.11, 0.2, 1), 4), gc.Equals, 0.2)
 }
@@ -0,0 +1,210 @@
+package cluster
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hoenirvili/cluster/util"
+)
+
+// SVGOptions changes how the dendrogram is drawn as svg
+type SVGOptions struct {
+	// Width and Height are the size of the image in pixels, if one of
+	// them is not positive 640 pixels of width or 480 of height are used
+	Width, Height int
+	// Horizontal draws the leaves from top to bottom on the left
+	// and the heights from left to right, by default the leaves are
+	// drawn from left to right on the bottom and the heights upwards
+	Horizontal bool
+	// Labels are the labels of the leaves, in the order of the leaves.
+	// If it is empty the names of the leaves are used
+	Labels []string
+	// Order is the order the leaves are drawn in.
+	// If it is empty the order returned by Order is used
+	Order []int
+	// Cut is the height where a dashed line is drawn across the
+	// dendrogram, it is drawn only if it is positive
+	Cut float64
+}
+
+// the space in pixels around the dendrogram, for the axis
+// and for every character of the longest label
+const (
+	svgPadding   = 20.0
+	svgAxisSpace = 50.0
+	svgCharSpace = 7.0
+)
+
+// WriteSVG draws the dendrogram as a svg image, the leaves along one
+// axis and the heights of the merges along the other.
+// Every merge is drawn as a line joining the two clusters merged
+// at the height of their distance.
+// The same dendrogram and options always give the same image.
+// If the labels or the order do not match the leaves or the image is too
+// small to hold the axis and the labels this will return an error
+func (d Dendrogram) WriteSVG(w io.Writer, options SVGOptions) error {
+	n := len(d.Leaves)
+	if n == 0 {
+		return nil
+	}
+
+	labels, err := d.labels(options.Labels)
+	if err != nil {
+		return err
+	}
+	order := options.Order
+	if len(order) == 0 {
+		order = d.Order()
+	}
+	row, err := leafRows(order, n)
+	if err != nil {
+		return err
+	}
+
+	width, height := float64(options.Width), float64(options.Height)
+	if options.Width <= 0 {
+		width = 640
+	}
+	if options.Height <= 0 {
+		height = 480
+	}
+
+	steps := d.steps()
+	children, _ := d.tree(steps)
+	top := 0.0
+	for _, s := range steps {
+		top = math.Max(top, s.distance)
+	}
+	if options.Cut > top {
+		top = options.Cut
+	}
+	if top == 0 {
+		top = 1
+	}
+
+	longest := 0
+	for _, label := range labels {
+		if l := utf8.RuneCountInString(label); l > longest {
+			longest = l
+		}
+	}
+
+	// the leaves are placed along the side of the given length and the
+	// heights along the other side, point turns a leaf position and
+	// a height into the coordinates of the image
+	side, length := width-svgAxisSpace-svgPadding, height-2*svgPadding-svgCharSpace*float64(longest)
+	point := func(position, h float64) (float64, float64) {
+		return svgAxisSpace + (position+0.5)*side/float64(n),
+			svgPadding + (1-h/top)*length
+	}
+	if options.Horizontal {
+		side, length = height-svgAxisSpace-svgPadding, width-2*svgPadding-svgCharSpace*float64(longest)
+		point = func(position, h float64) (float64, float64) {
+			return width - svgPadding - (1-h/top)*length,
+				svgPadding + (position+0.5)*side/float64(n)
+		}
+	}
+	if side <= 0 || length <= 0 {
+		return fmt.Errorf("cluster: %sx%s pixels are too small to draw the dendrogram",
+			svgNumber(width), svgNumber(height))
+	}
+
+	var svg strings.Builder
+	fmt.Fprintf(&svg, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height))
+
+	// position holds the position of every node along the leaves,
+	// the position of a merge is the middle of its two clusters
+	position := make([]float64, len(children), len(children))
+	for leaf := 0; leaf < n; leaf++ {
+		position[leaf] = float64(row[leaf])
+	}
+	svg.WriteString(`<g fill="none" stroke="black" stroke-width="1">` + "\n")
+	for i, s := range steps {
+		v := n + i
+		a, b := children[v][0], children[v][1]
+		position[v] = (position[a] + position[b]) / 2
+
+		ha, hb := 0.0, 0.0
+		if a >= n {
+			ha = steps[a-n].distance
+		}
+		if b >= n {
+			hb = steps[b-n].distance
+		}
+		x1, y1 := point(position[a], ha)
+		x2, y2 := point(position[a], s.distance)
+		x3, y3 := point(position[b], s.distance)
+		x4, y4 := point(position[b], hb)
+		fmt.Fprintf(&svg, `<path d="M %s %s L %s %s L %s %s L %s %s"/>`+"\n",
+			svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2),
+			svgNumber(x3), svgNumber(y3), svgNumber(x4), svgNumber(y4))
+	}
+	svg.WriteString("</g>\n")
+
+	svg.WriteString(`<g font-family="sans-serif" font-size="12">` + "\n")
+	for leaf, label := range labels {
+		x, y := point(position[leaf], 0)
+		if options.Horizontal {
+			fmt.Fprintf(&svg, `<text x="%s" y="%s" text-anchor="end" dominant-baseline="middle">`,
+				svgNumber(x-5), svgNumber(y))
+		} else {
+			fmt.Fprintf(&svg, `<text x="%s" y="%s" text-anchor="end" dominant-baseline="middle" transform="rotate(-90 %s %s)">`,
+				svgNumber(x), svgNumber(y+5), svgNumber(x), svgNumber(y+5))
+		}
+		xml.EscapeText(&svg, []byte(label))
+		svg.WriteString("</text>\n")
+	}
+
+	// the axis of the heights is drawn on the other side of the leaves
+	x1, y1 := point(-0.5, 0)
+	x2, y2 := point(-0.5, top)
+	if options.Horizontal {
+		x1, y1 = point(float64(n)-0.5, 0)
+		x2, y2 = point(float64(n)-0.5, top)
+	}
+	fmt.Fprintf(&svg, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="black"/>`+"\n",
+		svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2))
+	for _, h := range axisTicks(top, int(length/80)) {
+		if options.Horizontal {
+			x, y := point(float64(n)-0.5, h)
+			fmt.Fprintf(&svg, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="black"/>`+"\n",
+				svgNumber(x), svgNumber(y), svgNumber(x), svgNumber(y+5))
+			fmt.Fprintf(&svg, `<text x="%s" y="%s" text-anchor="middle" dominant-baseline="hanging">%s</text>`+"\n",
+				svgNumber(x), svgNumber(y+7), formatHeight(h))
+			continue
+		}
+		x, y := point(-0.5, h)
+		fmt.Fprintf(&svg, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="black"/>`+"\n",
+			svgNumber(x-5), svgNumber(y), svgNumber(x), svgNumber(y))
+		fmt.Fprintf(&svg, `<text x="%s" y="%s" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n",
+			svgNumber(x-7), svgNumber(y), formatHeight(h))
+	}
+	svg.WriteString("</g>\n")
+
+	if options.Cut > 0 {
+		x1, y1 := point(-0.5, options.Cut)
+		x2, y2 := point(float64(n)-0.5, options.Cut)
+		fmt.Fprintf(&svg, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="red" stroke-dasharray="4 4"/>`+"\n",
+			svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2))
+	}
+	svg.WriteString("</svg>\n")
+
+	_, err = io.WriteString(w, svg.String())
+	return err
+}
+
+// svgNumber formats the coordinate as a svg attribute
+func svgNumber(x float64) string {
+	return strconv.FormatFloat(util.Round(x, 2), 'f', -1, 64)
+}
+
+// formatHeight formats a height rounded to 4 decimals
+func formatHeight(h float64) string {
+	return strconv.FormatFloat(util.Round(h, 4), 'f', -1, 64)
+}
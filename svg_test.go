@@ -0,0 +1,117 @@
+package cluster_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	gc "gopkg.in/check.v1"
+)
+
+type svgSuite struct{}
+
+var _ = gc.Suite(&svgSuite{})
+
+// image holds the elements of a svg image
+type image struct {
+	Width  string `xml:"width,attr"`
+	Height string `xml:"height,attr"`
+	Groups []struct {
+		Paths []struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+		Texts []string `xml:"text"`
+	} `xml:"g"`
+	Lines []struct {
+		Dash string `xml:"stroke-dasharray,attr"`
+	} `xml:"line"`
+}
+
+func (s svgSuite) draw(c *gc.C, options cluster.SVGOptions) image {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0))
+	d := cluster.FitDendrogram(points, cluster.AverageLinkage)
+	var buf bytes.Buffer
+	c.Assert(d.WriteSVG(&buf, options), gc.IsNil)
+
+	var svg image
+	c.Assert(xml.Unmarshal(buf.Bytes(), &svg), gc.IsNil)
+	c.Assert(svg.Groups, gc.HasLen, 2)
+	return svg
+}
+
+func (s svgSuite) TestWriteSVG(c *gc.C) {
+	svg := s.draw(c, cluster.SVGOptions{})
+	c.Assert(svg.Width, gc.Equals, "640")
+	c.Assert(svg.Height, gc.Equals, "480")
+	// one path for every merge
+	c.Assert(svg.Groups[0].Paths, gc.HasLen, 7)
+	c.Assert(svg.Groups[1].Texts[:8], gc.DeepEquals,
+		[]string{"x1", "x2", "x3", "x4", "x5", "x6", "x7", "x8"})
+	c.Assert(svg.Lines, gc.HasLen, 0)
+
+	// every size that is not positive is replaced on its own
+	svg = s.draw(c, cluster.SVGOptions{Width: 300})
+	c.Assert(svg.Width, gc.Equals, "300")
+	c.Assert(svg.Height, gc.Equals, "480")
+	svg = s.draw(c, cluster.SVGOptions{Height: 200})
+	c.Assert(svg.Width, gc.Equals, "640")
+	c.Assert(svg.Height, gc.Equals, "200")
+}
+
+func (s svgSuite) TestWriteSVGOptions(c *gc.C) {
+	labels := []string{"a<b", "b", "c", "d", "e", "f", "g", "h"}
+	for _, horizontal := range []bool{false, true} {
+		svg := s.draw(c, cluster.SVGOptions{
+			Width:      300,
+			Height:     200,
+			Horizontal: horizontal,
+			Labels:     labels,
+			Cut:        1,
+		})
+		c.Assert(svg.Width, gc.Equals, "300")
+		c.Assert(svg.Height, gc.Equals, "200")
+		c.Assert(svg.Groups[0].Paths, gc.HasLen, 7)
+		c.Assert(svg.Groups[1].Texts[:8], gc.DeepEquals, labels)
+		c.Assert(svg.Lines, gc.HasLen, 1)
+		c.Assert(svg.Lines[0].Dash, gc.Equals, "4 4")
+	}
+}
+
+func (s svgSuite) TestWriteSVGDeterministic(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0))
+	for st := cluster.SingleLinkage; st <= cluster.WeightedLinkage; st++ {
+		d := cluster.FitDendrogram(points, st)
+		var first, second bytes.Buffer
+		c.Assert(d.WriteSVG(&first, cluster.SVGOptions{Cut: 0.5}), gc.IsNil)
+		c.Assert(d.WriteSVG(&second, cluster.SVGOptions{Cut: 0.5}), gc.IsNil)
+		c.Assert(first.String(), gc.Equals, second.String())
+		c.Assert(strings.HasPrefix(first.String(), "<svg "), gc.Equals, true)
+	}
+}
+
+func (s svgSuite) TestWriteSVGErrors(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	d := cluster.FitDendrogram(points, cluster.SingleLinkage)
+	var buf bytes.Buffer
+	err := d.WriteSVG(&buf, cluster.SVGOptions{Labels: []string{"a"}})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 4 leaves")
+	err = d.WriteSVG(&buf, cluster.SVGOptions{Order: []int{0, 1, 2, 4}})
+	c.Assert(err, gc.ErrorMatches, "cluster: invalid leaf 4 in the order")
+	c.Assert(buf.Len(), gc.Equals, 0)
+
+	// the labels take more space than the image has
+	labels := []string{strings.Repeat("a", 100), "b", "c", "d"}
+	for _, horizontal := range []bool{false, true} {
+		err = d.WriteSVG(&buf, cluster.SVGOptions{Labels: labels, Horizontal: horizontal})
+		c.Assert(err, gc.ErrorMatches, "cluster: 640x480 pixels are too small to draw the dendrogram")
+	}
+	err = d.WriteSVG(&buf, cluster.SVGOptions{Width: 60, Height: 60})
+	c.Assert(err, gc.ErrorMatches, "cluster: 60x60 pixels are too small to draw the dendrogram")
+	c.Assert(buf.Len(), gc.Equals, 0)
+
+	c.Assert(cluster.Dendrogram{}.WriteSVG(&buf, cluster.SVGOptions{}), gc.IsNil)
+	c.Assert(buf.Len(), gc.Equals, 0)
+}
@@ -0,0 +1,276 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// TextOptions changes how the dendrogram is drawn as text
+type TextOptions struct {
+	// Width is the number of columns used for the height axis,
+	// if it is not positive 60 columns are used
+	Width int
+	// ASCII draws the dendrogram only with ascii characters
+	// instead of the unicode box drawing characters
+	ASCII bool
+	// Labels are the labels of the leaves, in the order of the leaves.
+	// If it is empty the names of the leaves are used
+	Labels []string
+	// Order is the order the leaves are drawn in, from top to bottom.
+	// If it is empty the order returned by Order is used
+	Order []int
+}
+
+// the directions a line leaves a cell of the text grid
+const (
+	lineLeft uint8 = 1 << iota
+	lineRight
+	lineUp
+	lineDown
+)
+
+// boxChars maps the directions of a cell to the unicode box drawing character
+var boxChars = map[uint8]rune{
+	0:                                        ' ',
+	lineLeft:                                 '─',
+	lineRight:                                '─',
+	lineLeft | lineRight:                     '─',
+	lineUp:                                   '│',
+	lineDown:                                 '│',
+	lineUp | lineDown:                        '│',
+	lineLeft | lineDown:                      '┐',
+	lineLeft | lineUp:                        '┘',
+	lineRight | lineDown:                     '┌',
+	lineRight | lineUp:                       '└',
+	lineLeft | lineRight | lineDown:          '┬',
+	lineLeft | lineRight | lineUp:            '┴',
+	lineUp | lineDown | lineLeft:             '┤',
+	lineUp | lineDown | lineRight:            '├',
+	lineLeft | lineRight | lineUp | lineDown: '┼',
+}
+
+// boxChar returns the character drawn for the directions of a cell
+func boxChar(cell uint8, ascii bool) rune {
+	if !ascii {
+		return boxChars[cell]
+	}
+
+	switch cell {
+	case 0:
+		return ' '
+	case lineLeft, lineRight, lineLeft | lineRight:
+		return '-'
+	case lineUp, lineDown, lineUp | lineDown:
+		return '|'
+	}
+
+	return '+'
+}
+
+// WriteText draws the dendrogram as text, one leaf on every line
+// followed by the height axis. The leaves are drawn on the left and
+// every merge is drawn as a vertical line at the column of its distance,
+// joining the lines of the two clusters merged.
+// The same dendrogram and options always give the same text.
+// If the labels or the order do not match the leaves this will return an error
+func (d Dendrogram) WriteText(w io.Writer, options TextOptions) error {
+	n := len(d.Leaves)
+	if n == 0 {
+		return nil
+	}
+
+	labels, err := d.labels(options.Labels)
+	if err != nil {
+		return err
+	}
+	order := options.Order
+	if len(order) == 0 {
+		order = d.Order()
+	}
+	row, err := leafRows(order, n)
+	if err != nil {
+		return err
+	}
+
+	width := options.Width
+	if width <= 0 {
+		width = 60
+	}
+
+	steps := d.steps()
+	children, _ := d.tree(steps)
+	top := 0.0
+	for _, s := range steps {
+		top = math.Max(top, s.distance)
+	}
+	column := func(distance float64) int {
+		if top == 0 {
+			return 0
+		}
+		return int(math.Round(distance / top * float64(width-1)))
+	}
+
+	grid := make([][]uint8, n, n)
+	for i := range grid {
+		grid[i] = make([]uint8, width, width)
+		grid[i][0] = lineRight
+	}
+
+	// attach holds the row where every node is joined to its parent
+	attach := make([]int, len(children), len(children))
+	copy(attach, row)
+	for i, s := range steps {
+		v := n + i
+		x := column(s.distance)
+		a, b := attach[children[v][0]], attach[children[v][1]]
+		if b < a {
+			a, b = b, a
+		}
+		attach[v] = a
+
+		for _, child := range children[v] {
+			from := 0
+			if child >= n {
+				from = column(steps[child-n].distance)
+			}
+			horizontalLine(grid[attach[child]], from, x)
+		}
+		for y := a; y < b; y++ {
+			grid[y][x] |= lineDown
+			grid[y+1][x] |= lineUp
+		}
+	}
+
+	width = 0
+	for _, label := range labels {
+		if l := utf8.RuneCountInString(label); l > width {
+			width = l
+		}
+	}
+
+	var text strings.Builder
+	for _, leaf := range order {
+		fmt.Fprintf(&text, "%-*s ", width, labels[leaf])
+		line := make([]rune, 0, len(grid[row[leaf]]))
+		for _, cell := range grid[row[leaf]] {
+			line = append(line, boxChar(cell, options.ASCII))
+		}
+		text.WriteString(strings.TrimRight(string(line), " "))
+		text.WriteString("\n")
+	}
+	textAxis(&text, width+1, len(grid[0]), top, options.ASCII)
+
+	_, err = io.WriteString(w, text.String())
+	return err
+}
+
+// labels returns the labels drawn for the leaves, the names of the leaves
+// if there are no labels. If the labels do not match the leaves this
+// will return an error
+func (d Dendrogram) labels(labels []string) ([]string, error) {
+	n := len(d.Leaves)
+	if len(labels) == 0 {
+		labels = make([]string, n, n)
+		for i, leaf := range d.Leaves {
+			labels[i] = string(leaf)
+		}
+	}
+	if len(labels) != n {
+		return nil, fmt.Errorf("cluster: %d labels for %d leaves", len(labels), n)
+	}
+
+	return labels, nil
+}
+
+// leafRows returns the row of every leaf drawn in the order.
+// If the order is not a permutation of the n leaves
+// this will return an error
+func leafRows(order []int, n int) ([]int, error) {
+	if len(order) != n {
+		return nil, fmt.Errorf("cluster: order of %d leaves for %d leaves", len(order), n)
+	}
+
+	row := make([]int, n, n)
+	for i := range row {
+		row[i] = -1
+	}
+	for i, leaf := range order {
+		if leaf < 0 || leaf >= n || row[leaf] != -1 {
+			return nil, fmt.Errorf("cluster: invalid leaf %d in the order", leaf)
+		}
+		row[leaf] = i
+	}
+
+	return row, nil
+}
+
+// horizontalLine draws a horizontal line on the row from the column from to the column to
+func horizontalLine(row []uint8, from, to int) {
+	if to < from {
+		from, to = to, from
+	}
+	for x := from; x < to; x++ {
+		row[x] |= lineRight
+		row[x+1] |= lineLeft
+	}
+}
+
+// textAxis draws the height axis of the given width, starting after the
+// given margin, with a tick and its height at every round height
+func textAxis(text *strings.Builder, margin, width int, top float64, ascii bool) {
+	line := []rune(strings.Repeat(string(boxChar(lineLeft|lineRight, ascii)), width))
+	labels := []rune(strings.Repeat(" ", margin+width))
+	end := 0
+	for _, height := range axisTicks(top, (width-1)/10) {
+		x := 0
+		if top > 0 {
+			x = int(math.Round(height / top * float64(width-1)))
+		}
+		line[x] = boxChar(lineLeft|lineRight|lineDown, ascii)
+
+		label := []rune(formatHeight(height))
+		if margin+x < end {
+			continue
+		}
+		for len(labels) < margin+x+len(label) {
+			labels = append(labels, ' ')
+		}
+		copy(labels[margin+x:], label)
+		end = margin + x + len(label) + 1
+	}
+
+	text.WriteString(strings.Repeat(" ", margin))
+	text.WriteString(string(line))
+	text.WriteString("\n")
+	text.WriteString(strings.TrimRight(string(labels), " "))
+	text.WriteString("\n")
+}
+
+// axisTicks returns the heights of the ticks of an axis, starting from 0 up to
+// top, about count ticks after 0. The distance between two ticks is a round
+// number, 1, 2, 2.5 or 5 multiplied by a power of 10
+func axisTicks(top float64, count int) []float64 {
+	if top <= 0 || count < 1 {
+		return []float64{0}
+	}
+
+	raw := top / float64(count)
+	power := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := 10 * power
+	for _, nice := range []float64{1, 2, 2.5, 5} {
+		if nice*power >= raw {
+			step = nice * power
+			break
+		}
+	}
+
+	heights := make([]float64, 0, count+1)
+	for i := 0; float64(i)*step <= top*(1+1e-9); i++ {
+		heights = append(heights, float64(i)*step)
+	}
+
+	return heights
+}
@@ -0,0 +1,116 @@
+package cluster_test
+
+import (
+	"bytes"
+
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type textSuite struct{}
+
+var _ = gc.Suite(&textSuite{})
+
+func (t textSuite) dendrogram() *cluster.Dendrogram {
+	points := distance.NewDistances(one.NewDistances(0, 1, 3, 7))
+	return cluster.FitDendrogram(points, cluster.SingleLinkage)
+}
+
+func (t textSuite) TestWriteText(c *gc.C) {
+	var buf bytes.Buffer
+	err := t.dendrogram().WriteText(&buf, cluster.TextOptions{Width: 21})
+	c.Assert(err, gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"x1 ─────┬────┬─────────┐\n"+
+		"x2 ─────┘    │         │\n"+
+		"x3 ──────────┘         │\n"+
+		"x4 ────────────────────┘\n"+
+		"   ┬─────────┬─────────┬\n"+
+		"   0         2         4\n")
+}
+
+func (t textSuite) TestWriteTextASCII(c *gc.C) {
+	var buf bytes.Buffer
+	err := t.dendrogram().WriteText(&buf, cluster.TextOptions{Width: 21, ASCII: true})
+	c.Assert(err, gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"x1 -----+----+---------+\n"+
+		"x2 -----+    |         |\n"+
+		"x3 ----------+         |\n"+
+		"x4 --------------------+\n"+
+		"   +---------+---------+\n"+
+		"   0         2         4\n")
+}
+
+func (t textSuite) TestWriteTextLabelsOrder(c *gc.C) {
+	var buf bytes.Buffer
+	err := t.dendrogram().WriteText(&buf, cluster.TextOptions{
+		Width:  21,
+		ASCII:  true,
+		Labels: []string{"a", "b", "c", "d"},
+		Order:  []int{3, 2, 1, 0},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"d --------------------+\n"+
+		"c ----------+---------+\n"+
+		"b -----+----+\n"+
+		"a -----+\n"+
+		"  +---------+---------+\n"+
+		"  0         2         4\n")
+}
+
+func (t textSuite) TestWriteTextMultibyteLabels(c *gc.C) {
+	var buf bytes.Buffer
+	err := t.dendrogram().WriteText(&buf, cluster.TextOptions{
+		Width:  21,
+		ASCII:  true,
+		Labels: []string{"ñé", "ab", "é", "c"},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, ""+
+		"ñé -----+----+---------+\n"+
+		"ab -----+    |         |\n"+
+		"é  ----------+         |\n"+
+		"c  --------------------+\n"+
+		"   +---------+---------+\n"+
+		"   0         2         4\n")
+}
+
+func (t textSuite) TestWriteTextDeterministic(c *gc.C) {
+	points := distance.NewDistances(one.NewDistances(-0.3, 0.1, 0.2, 0.4, 1.6, 1.7, 1.9, 2.0))
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		d := cluster.FitDendrogram(points, s)
+		var first, second bytes.Buffer
+		c.Assert(d.WriteText(&first, cluster.TextOptions{}), gc.IsNil)
+		c.Assert(d.WriteText(&second, cluster.TextOptions{}), gc.IsNil)
+		c.Assert(first.String(), gc.Equals, second.String())
+		// every leaf and the two lines of the axis
+		c.Assert(bytes.Count(first.Bytes(), []byte("\n")), gc.Equals, len(points)+2)
+	}
+}
+
+func (t textSuite) TestWriteTextErrors(c *gc.C) {
+	d := t.dendrogram()
+	var buf bytes.Buffer
+	err := d.WriteText(&buf, cluster.TextOptions{Labels: []string{"a"}})
+	c.Assert(err, gc.ErrorMatches, "cluster: 1 labels for 4 leaves")
+	err = d.WriteText(&buf, cluster.TextOptions{Order: []int{0, 1, 2}})
+	c.Assert(err, gc.ErrorMatches, "cluster: order of 3 leaves for 4 leaves")
+	err = d.WriteText(&buf, cluster.TextOptions{Order: []int{0, 1, 1, 3}})
+	c.Assert(err, gc.ErrorMatches, "cluster: invalid leaf 1 in the order")
+	c.Assert(buf.Len(), gc.Equals, 0)
+}
+
+func (t textSuite) TestWriteTextEmpty(c *gc.C) {
+	var buf bytes.Buffer
+	c.Assert(cluster.Dendrogram{}.WriteText(&buf, cluster.TextOptions{}), gc.IsNil)
+	c.Assert(buf.Len(), gc.Equals, 0)
+
+	d := cluster.Dendrogram{Leaves: []set.Set{"x1"}}
+	c.Assert(d.WriteText(&buf, cluster.TextOptions{Width: 10}), gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, "x1 ─\n   ┬─────────\n   0\n")
+}
@@ -0,0 +1,80 @@
+package cluster
+
+import "math/rand"
+
+// pair holds the rows of two clusters of the matrix
+type pair struct {
+	i, j int
+}
+
+// TieBreak is the policy used to choose which pair of clusters is merged
+// when several pairs are at the same distance. Every policy is deterministic,
+// fitting the same input with the same policy always gives the same merges
+type TieBreak interface {
+	// breaker returns the function that chooses the pair merged during
+	// one fit. The candidates are sorted by their rows and born holds,
+	// for every row, the number of the merge that created its cluster
+	// where the leaves are created by the merge 0
+	breaker() func(candidates []pair, born []int) int
+}
+
+// lowestIndex merges the pair with the lowest rows first
+type lowestIndex struct{}
+
+func (lowestIndex) breaker() func([]pair, []int) int {
+	return func([]pair, []int) int { return 0 }
+}
+
+// LowestIndex returns the policy that merges first the pair of clusters
+// with the lowest row and column in the table of distances.
+// This is the policy Fit uses
+func LowestIndex() TieBreak {
+	return lowestIndex{}
+}
+
+// newestFirst merges first the pair holding the newest cluster
+type newestFirst struct{}
+
+func (newestFirst) breaker() func([]pair, []int) int {
+	return func(candidates []pair, born []int) int {
+		best, newest := 0, -1
+		for c, p := range candidates {
+			n := born[p.i]
+			if born[p.j] > n {
+				n = born[p.j]
+			}
+			if n > newest {
+				best, newest = c, n
+			}
+		}
+
+		return best
+	}
+}
+
+// NewestFirst returns the policy that merges first the pair holding
+// the cluster created by the most recent merge. If the pairs hold
+// only leaves or clusters equally new, the lowest row and column
+// in the table of distances is merged first
+func NewestFirst() TieBreak {
+	return newestFirst{}
+}
+
+// seededRandom picks one of the pairs at random
+type seededRandom struct {
+	seed int64
+}
+
+func (s seededRandom) breaker() func([]pair, []int) int {
+	r := rand.New(rand.NewSource(s.seed))
+	return func(candidates []pair, _ []int) int {
+		return r.Intn(len(candidates))
+	}
+}
+
+// SeededRandom returns the policy that picks one of the pairs at random.
+// The random numbers are generated from the seed, starting again for every
+// fit, so the same input and seed always give the same merges
+func SeededRandom(seed int64) TieBreak {
+	return seededRandom{seed: seed}
+}
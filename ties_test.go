@@ -0,0 +1,89 @@
+package cluster_test
+
+import (
+	"github.com/hoenirvili/cluster"
+	"github.com/hoenirvili/cluster/dimension/one"
+	"github.com/hoenirvili/cluster/distance"
+	"github.com/hoenirvili/cluster/set"
+	gc "gopkg.in/check.v1"
+)
+
+type tiesSuite struct{}
+
+var _ = gc.Suite(&tiesSuite{})
+
+// distances returns a table where the pair x1, x2 and the pair
+// of the cluster x3, x4 with x5 are at the same distance
+func (t tiesSuite) distances(c *gc.C) []distance.Distance {
+	points := one.NewDistances(0, 1, 10, 10.5, 11.5)
+	c.Assert(points, gc.NotNil)
+	return distance.NewDistances(points)
+}
+
+func (t tiesSuite) TestLowestIndex(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		expected := cluster.FitDendrogram(distances, s)
+		dendrogram := cluster.FitDendrogramTies(distances, s, cluster.LowestIndex())
+		c.Assert(dendrogram, gc.DeepEquals, expected)
+		dendrogram = cluster.FitDendrogramTies(distances, s, nil)
+		c.Assert(dendrogram, gc.DeepEquals, expected)
+
+		for k := len(distances); k > 0; k-- {
+			clusters := cluster.FitTies(distances, s, k, cluster.LowestIndex())
+			c.Assert(clusters, gc.DeepEquals, cluster.Fit(distances, s, k))
+		}
+	}
+}
+
+func (t tiesSuite) TestNewestFirst(c *gc.C) {
+	distances := t.distances(c)
+
+	dendrogram := cluster.FitDendrogramTies(distances, cluster.SingleLinkage, cluster.LowestIndex())
+	c.Assert(dendrogram.Merges, gc.DeepEquals, []cluster.Merge{
+		{First: "x3", Second: "x4", Distance: 0.5, Size: 2},
+		{First: "x1", Second: "x2", Distance: 1, Size: 2},
+		{First: "x3,x4", Second: "x5", Distance: 1, Size: 3},
+		{First: "x1,x2", Second: "x3,x4,x5", Distance: 9, Size: 5},
+	})
+
+	dendrogram = cluster.FitDendrogramTies(distances, cluster.SingleLinkage, cluster.NewestFirst())
+	c.Assert(dendrogram.Merges, gc.DeepEquals, []cluster.Merge{
+		{First: "x3", Second: "x4", Distance: 0.5, Size: 2},
+		{First: "x3,x4", Second: "x5", Distance: 1, Size: 3},
+		{First: "x1", Second: "x2", Distance: 1, Size: 2},
+		{First: "x1,x2", Second: "x3,x4,x5", Distance: 9, Size: 5},
+	})
+
+	clusters := cluster.FitTies(distances, cluster.SingleLinkage, 3, cluster.NewestFirst())
+	c.Assert(clusters, gc.DeepEquals, []set.Set{"x1", "x2", "x3,x4,x5"})
+}
+
+func (t tiesSuite) TestSeededRandom(c *gc.C) {
+	distances := clusterSuite{}.oneDistances(c)
+	for s := cluster.SingleLinkage; s <= cluster.WeightedLinkage; s++ {
+		for seed := int64(0); seed < 5; seed++ {
+			expected := cluster.FitDendrogramTies(distances, s, cluster.SeededRandom(seed))
+			dendrogram := cluster.FitDendrogramTies(distances, s, cluster.SeededRandom(seed))
+			c.Assert(dendrogram, gc.DeepEquals, expected)
+			c.Assert(dendrogram.Merges, gc.HasLen, len(distances)-1)
+		}
+	}
+}
+
+func (t tiesSuite) TestDeterministic(c *gc.C) {
+	distances := t.distances(c)
+	policies := []cluster.TieBreak{
+		cluster.LowestIndex(),
+		cluster.NewestFirst(),
+		cluster.SeededRandom(42),
+	}
+
+	for _, policy := range policies {
+		expected := cluster.FitDendrogramTies(distances, cluster.AverageLinkage, policy)
+		for i := 0; i < 10; i++ {
+			dendrogram := cluster.FitDendrogramTies(distances, cluster.AverageLinkage, policy)
+			c.Assert(dendrogram, gc.DeepEquals, expected)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Package wardlinkage provides basic semantics for choosing different
+// clusters that are best fitted for ward's minimum variance clustering
+package wardlinkage
+
+import "github.com/hoenirvili/cluster/lancewilliams"
+
+// WardLinkage type that represents the ward linkage
+// bottom up cluster semantics
+type WardLinkage struct{}
+
+// NewWardLinkage creates a new WardLinkage pointer
+func NewWardLinkage() *WardLinkage {
+	return &WardLinkage{}
+}
+
+var (
+	_ lancewilliams.Linkage   = (*WardLinkage)(nil)
+	_ lancewilliams.Reducible = (*WardLinkage)(nil)
+)
+
+// Coefficients returns the ward linkage coefficients
+// αi = (ni+nk)/n, αj = (nj+nk)/n, β = -nk/n and γ = 0
+// where n = ni+nj+nk
+func (w WardLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	n := float64(ni + nj + nk)
+	return lancewilliams.Coefficients{
+		AlphaI: float64(ni+nk) / n,
+		AlphaJ: float64(nj+nk) / n,
+		Beta:   -float64(nk) / n,
+	}
+}
+
+// Squared returns true, the ward linkage formula is
+// applied on the squared euclidean distances
+func (w WardLinkage) Squared() bool { return true }
+
+// Reducible returns true, the ward linkage satisfies
+// the reducibility property
+func (w WardLinkage) Reducible() bool { return true }
@@ -0,0 +1,29 @@
+package wardlinkage_test
+
+import (
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/wardlinkage"
+	gc "gopkg.in/check.v1"
+)
+
+type wardLinkageSuite struct{}
+
+var _ = gc.Suite(&wardLinkageSuite{})
+
+func (w wardLinkageSuite) TestNewWardLinkage(c *gc.C) {
+	wl := wardlinkage.NewWardLinkage()
+	c.Assert(wl, gc.NotNil)
+	c.Assert(wl.Squared(), gc.Equals, true)
+}
+
+func (w wardLinkageSuite) TestWardLinkageCoefficients(c *gc.C) {
+	wl := wardlinkage.NewWardLinkage()
+	coefficients := wl.Coefficients(1, 2, 1)
+	c.Assert(coefficients, gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.5, AlphaJ: 0.75, Beta: -0.25, Gamma: 0,
+	})
+
+	// (2*16 + 2*9 - 1*1) / 3 = 49 / 3
+	coefficients = wl.Coefficients(1, 1, 1)
+	c.Assert(coefficients.Distance(16, 9, 1), gc.Equals, 49.0/3.0)
+}
@@ -0,0 +1,34 @@
+// Package weightedlinkage provides basic semantics for choosing different
+// clusters that are best fitted for weighted average clustering (WPGMA)
+package weightedlinkage
+
+import "github.com/hoenirvili/cluster/lancewilliams"
+
+// WeightedLinkage type that represents the weighted average linkage
+// bottom up cluster semantics, also known as McQuitty's method
+type WeightedLinkage struct{}
+
+// NewWeightedLinkage creates a new WeightedLinkage pointer
+func NewWeightedLinkage() *WeightedLinkage {
+	return &WeightedLinkage{}
+}
+
+var (
+	_ lancewilliams.Linkage   = (*WeightedLinkage)(nil)
+	_ lancewilliams.Reducible = (*WeightedLinkage)(nil)
+)
+
+// Coefficients returns the weighted average linkage coefficients
+// αi = αj = 1/2, β = 0 and γ = 0, this will keep the plain mean
+// of the two distances no matter the size of the clusters
+func (w WeightedLinkage) Coefficients(ni, nj, nk int) lancewilliams.Coefficients {
+	return lancewilliams.Coefficients{AlphaI: 0.5, AlphaJ: 0.5}
+}
+
+// Squared returns false, the weighted average linkage formula is
+// applied on the distances
+func (w WeightedLinkage) Squared() bool { return false }
+
+// Reducible returns true, the weighted average linkage satisfies
+// the reducibility property
+func (w WeightedLinkage) Reducible() bool { return true }
@@ -0,0 +1,29 @@
+package weightedlinkage_test
+
+import (
+	"github.com/hoenirvili/cluster/lancewilliams"
+	"github.com/hoenirvili/cluster/util"
+	"github.com/hoenirvili/cluster/weightedlinkage"
+	gc "gopkg.in/check.v1"
+)
+
+type weightedLinkageSuite struct{}
+
+var _ = gc.Suite(&weightedLinkageSuite{})
+
+func (w weightedLinkageSuite) TestNewWeightedLinkage(c *gc.C) {
+	wl := weightedlinkage.NewWeightedLinkage()
+	c.Assert(wl, gc.NotNil)
+	c.Assert(wl.Squared(), gc.Equals, false)
+}
+
+func (w weightedLinkageSuite) TestWeightedLinkageCoefficients(c *gc.C) {
+	wl := weightedlinkage.NewWeightedLinkage()
+	coefficients := wl.Coefficients(1, 3, 2)
+	c.Assert(coefficients, gc.DeepEquals, lancewilliams.Coefficients{
+		AlphaI: 0.5, AlphaJ: 0.5, Beta: 0, Gamma: 0,
+	})
+
+	// the size of the clusters does not matter
+	c.Assert(util.Round(coefficients.Distance(4, 2, 1), 4), gc.Equals, 3.0)
+}